@@ -0,0 +1,25 @@
+//go:build linux
+
+package path
+
+// ファイル領域の確保 (Linux)
+
+import (
+	"errors"
+	"os"
+	"syscall"
+)
+
+// fallocate で領域を確保、未対応の場合はゼロを書き込む
+func allocate(f *os.File, size int64) error {
+	// 長さ 0 の fallocate は EINVAL になるため何もしない
+	// 既存のスパースファイルにも実際の領域を確保するため、ファイルサイズでは判定しない
+	if size <= 0 {
+		return nil
+	}
+	err := syscall.Fallocate(int(f.Fd()), 0, 0, size)
+	if errors.Is(err, syscall.EOPNOTSUPP) || errors.Is(err, syscall.ENOSYS) {
+		return writeZeros(f, size)
+	}
+	return err
+}
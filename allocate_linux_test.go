@@ -0,0 +1,36 @@
+//go:build linux
+
+package path
+
+import (
+	"os"
+	"syscall"
+	"testing"
+)
+
+func TestAllocateReservesBlocks(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.bin")
+	blocks := func() int64 {
+		var st syscall.Stat_t
+		if err := syscall.Stat(p.String(), &st); err != nil {
+			t.Fatal(err)
+		}
+		return st.Blocks * 512
+	}
+	if err := p.CreateSized(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+	if blocks() >= 1<<20 {
+		t.Skip("file system does not support sparse files")
+	}
+	// スパースファイルでも実際の領域を確保する
+	if err := p.Allocate(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+	if got := blocks(); got < 1<<20 {
+		t.Errorf("allocated %d bytes", got)
+	}
+	if fi, _ := os.Stat(p.String()); fi.Size() != 1<<20 {
+		t.Errorf("size = %d", fi.Size())
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package path
+
+// ファイル領域の確保 (Linux 以外)
+
+import (
+	"os"
+)
+
+// ゼロを書き込んで領域を確保
+func allocate(f *os.File, size int64) error {
+	return writeZeros(f, size)
+}
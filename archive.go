@@ -0,0 +1,261 @@
+package path
+
+// アーカイブ(zip など)を扱う処理
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"compress/gzip"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Entries を zip アーカイブとして w に書き出す
+// アーカイブ内のパスは root からの相対パスで格納する
+// ディレクトリは配下を再帰的に格納し、シンボリックリンクはリンク先を辿らずリンクとして格納する
+func (e Entries) WriteZip(w io.Writer, root Path) error {
+	zw := zip.NewWriter(w)
+	for _, entry := range e {
+		err := filepath.WalkDir(string(entry), func(path string, d fs.DirEntry, err error) error {
+			if err != nil {
+				return err
+			}
+			return addZipEntry(zw, NewPath(path), root)
+		})
+		if err != nil {
+			zw.Close()
+			return err
+		}
+	}
+	return zw.Close()
+}
+
+// zip アーカイブ内の名前を取得、root の外側にある場合はエラー
+func zipName(p Path, root Path) (string, error) {
+	rel, err := filepath.Rel(string(root), string(p))
+	if err != nil {
+		return "", err
+	}
+	if isOutside(rel) {
+		return "", fmt.Errorf("%s is not under %s", p, root)
+	}
+	return filepath.ToSlash(rel), nil
+}
+
+// 1 つのパスを zip アーカイブに追加
+func addZipEntry(zw *zip.Writer, p Path, root Path) error {
+	fi, err := os.Lstat(string(p))
+	if err != nil {
+		return err
+	}
+	name, err := zipName(p, root)
+	if err != nil {
+		return err
+	}
+	if name == "." {
+		// root 自身は格納しない
+		return nil
+	}
+
+	header, err := zip.FileInfoHeader(fi)
+	if err != nil {
+		return err
+	}
+	header.Name = name
+	switch {
+	case fi.IsDir():
+		header.Name += "/"
+	case fi.Mode()&os.ModeSymlink != 0:
+		// リンク先のパスを内容として格納
+	default:
+		header.Method = zip.Deflate
+	}
+
+	w, err := zw.CreateHeader(header)
+	if err != nil {
+		return err
+	}
+	switch {
+	case fi.IsDir():
+		return nil
+	case fi.Mode()&os.ModeSymlink != 0:
+		target, err := os.Readlink(string(p))
+		if err != nil {
+			return err
+		}
+		_, err = io.WriteString(w, target)
+		return err
+	}
+
+	f, err := os.Open(string(p))
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// Entries を拡張子ごとに分け、拡張子ごとの zip アーカイブを dstDir に作成する
+// アーカイブ名は拡張子の名前 (.txt なら txt.zip、拡張子なしは noext.zip) とし、
+// アーカイブ内のパスは root からの相対パスで格納する。作成したアーカイブを返す
+func (e Entries) ZipByExt(dstDir Path, root Path) (Entries, error) {
+	groups := map[Ext]Entries{}
+	for _, entry := range e {
+		groups[entry.Ext()] = append(groups[entry.Ext()], entry)
+	}
+	archives := Entries{}
+	for _, ext := range e.ToExt() {
+		name := strings.TrimPrefix(ext.String(), ".")
+		if name == "" {
+			name = "noext"
+		}
+		dst := Join(dstDir, NewPath(name+".zip"))
+		err := dst.writeAtomic(func(w io.Writer) error {
+			return groups[ext].WriteZip(w, root)
+		})
+		if err != nil {
+			return nil, err
+		}
+		archives = append(archives, dst)
+	}
+	return archives, nil
+}
+
+// zip アーカイブ内のパスを展開せずに取得
+func (p Path) ZipEntries() (Entries, error) {
+	zr, err := zip.OpenReader(string(p))
+	if err != nil {
+		return nil, err
+	}
+	defer zr.Close()
+	entries := make(Entries, len(zr.File))
+	for i, f := range zr.File {
+		entries[i] = archiveEntryPath(f.Name)
+	}
+	return entries, nil
+}
+
+// tar アーカイブ (gzip 圧縮されたものも含む) 内のパスを展開せずに取得
+func (p Path) TarEntries() (Entries, error) {
+	f, err := p.FileOpen()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	tr, err := newTarReader(f)
+	if err != nil {
+		return nil, err
+	}
+	entries := Entries{}
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return entries, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		entries = append(entries, archiveEntryPath(h.Name))
+	}
+}
+
+// tar の Reader を作成、gzip 圧縮されている場合は展開しながら読む
+func newTarReader(r io.Reader) (*tar.Reader, error) {
+	br := bufio.NewReader(r)
+	magic, err := br.Peek(2)
+	if err == nil && magic[0] == 0x1f && magic[1] == 0x8b {
+		gr, err := gzip.NewReader(br)
+		if err != nil {
+			return nil, err
+		}
+		return tar.NewReader(gr), nil
+	}
+	return tar.NewReader(br), nil
+}
+
+// アーカイブ内の名前をパスに変換
+func archiveEntryPath(name string) Path {
+	return NewPath(filepath.FromSlash(strings.TrimSuffix(name, "/")))
+}
+
+// アーカイブ内の名前を展開先のパスに変換、展開先の外側を指す場合はエラー (zip slip 対策)
+func safeJoin(root Path, name string) (Path, error) {
+	p := Join(root, NewPath(filepath.FromSlash(name)))
+	rel, err := filepath.Rel(string(root), string(p))
+	if err != nil || isOutside(rel) || filepath.IsAbs(filepath.FromSlash(name)) {
+		return "", fmt.Errorf("illegal path in archive: %s", name)
+	}
+	return p, nil
+}
+
+// zip または tar (gzip 圧縮されたものも含む) アーカイブから member のみを dst ディレクトリに展開
+// member がアーカイブ内にない場合はエラー
+func (p Path) ExtractOne(member Path, dst Path) error {
+	f, err := p.FileOpen()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	out, err := safeJoin(dst, filepath.ToSlash(string(member)))
+	if err != nil {
+		return err
+	}
+	member = member.Clean()
+
+	magic := make([]byte, 4)
+	if _, err := io.ReadFull(f, magic); err == nil && string(magic) == "PK\x03\x04" {
+		fi, err := f.Stat()
+		if err != nil {
+			return err
+		}
+		zr, err := zip.NewReader(f, fi.Size())
+		if err != nil {
+			return err
+		}
+		for _, zf := range zr.File {
+			if archiveEntryPath(zf.Name).Clean() != member || zf.FileInfo().IsDir() {
+				continue
+			}
+			r, err := zf.Open()
+			if err != nil {
+				return err
+			}
+			defer r.Close()
+			return out.writeAtomicPerm(zf.Mode().Perm(), func(w io.Writer) error {
+				_, err := io.Copy(w, r)
+				return err
+			})
+		}
+		return fmt.Errorf("%s: %s: %w", p, member, os.ErrNotExist)
+	}
+
+	if _, err := f.Seek(0, io.SeekStart); err != nil {
+		return err
+	}
+	tr, err := newTarReader(f)
+	if err != nil {
+		return err
+	}
+	for {
+		h, err := tr.Next()
+		if err == io.EOF {
+			return fmt.Errorf("%s: %s: %w", p, member, os.ErrNotExist)
+		}
+		if err != nil {
+			return err
+		}
+		if archiveEntryPath(h.Name).Clean() != member || h.Typeflag != tar.TypeReg {
+			continue
+		}
+		return out.writeAtomicPerm(h.FileInfo().Mode().Perm(), func(w io.Writer) error {
+			_, err := io.Copy(w, tr)
+			return err
+		})
+	}
+}
@@ -0,0 +1,152 @@
+package path
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"errors"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"testing"
+)
+
+func TestWriteZip(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "hello", "sub/b.txt": "world"})
+	if err := os.Symlink("a.txt", Join(root, "link").String()); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+
+	var buf bytes.Buffer
+	if err := (Entries{Join(root, "a.txt"), Join(root, "sub"), Join(root, "link")}).WriteZip(&buf, root); err != nil {
+		t.Fatal(err)
+	}
+	zr, err := zip.NewReader(bytes.NewReader(buf.Bytes()), int64(buf.Len()))
+	if err != nil {
+		t.Fatal(err)
+	}
+	got := map[string]string{}
+	for _, f := range zr.File {
+		r, err := f.Open()
+		if err != nil {
+			t.Fatal(err)
+		}
+		data, _ := io.ReadAll(r)
+		r.Close()
+		got[f.Name] = string(data)
+		if f.Name == "link" && f.Mode()&os.ModeSymlink == 0 {
+			t.Error("symlink stored as a regular file")
+		}
+	}
+	want := map[string]string{"a.txt": "hello", "sub/": "", "sub/b.txt": "world", "link": "a.txt"}
+	if !maps.Equal(got, want) {
+		t.Errorf("entries = %q, want %q", got, want)
+	}
+
+	if err := (Entries{root}).WriteZip(io.Discard, Join(root, "sub")); err == nil {
+		t.Error("WriteZip outside root should fail")
+	}
+}
+
+func TestZipByExt(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "", "b.txt": "", "c.md": "", "README": ""})
+	entries, _ := root.Entries()
+	slices.Sort(entries)
+	dst := Join(NewPath(t.TempDir()), "out")
+	archives, err := entries.ZipByExt(dst, root)
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[Path][]string{"noext.zip": {"README"}, "md.zip": {"c.md"}, "txt.zip": {"a.txt", "b.txt"}}
+	if len(archives) != len(want) {
+		t.Fatalf("ZipByExt = %v", archives)
+	}
+	for _, a := range archives {
+		zr, err := zip.OpenReader(a.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		var names []string
+		for _, f := range zr.File {
+			names = append(names, f.Name)
+		}
+		zr.Close()
+		if w := want[a.Base()]; !slices.Equal(names, w) {
+			t.Errorf("%s = %v, want %v", a.Base(), names, w)
+		}
+	}
+}
+
+// テスト用の tar.gz アーカイブを作成
+func writeTestTarGz(t *testing.T, p Path, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	gw := gzip.NewWriter(&buf)
+	tw := tar.NewWriter(gw)
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		tw.WriteHeader(&tar.Header{Name: name, Mode: 0640, Size: int64(len(files[name])), Typeflag: tar.TypeReg})
+		io.WriteString(tw, files[name])
+	}
+	tw.Close()
+	gw.Close()
+	writeTestFile(t, p, buf.String())
+}
+
+// テスト用の zip アーカイブを作成
+func writeTestZip(t *testing.T, p Path, files map[string]string) {
+	t.Helper()
+	var buf bytes.Buffer
+	zw := zip.NewWriter(&buf)
+	for _, name := range slices.Sorted(maps.Keys(files)) {
+		w, _ := zw.Create(name)
+		io.WriteString(w, files[name])
+	}
+	zw.Close()
+	writeTestFile(t, p, buf.String())
+}
+
+func TestArchiveEntries(t *testing.T) {
+	root := NewPath(t.TempDir())
+	files := map[string]string{"a.txt": "A", "dir/b.txt": "B"}
+	zipPath, tarPath := Join(root, "a.zip"), Join(root, "a.tar.gz")
+	writeTestZip(t, zipPath, files)
+	writeTestTarGz(t, tarPath, files)
+
+	want := Entries{"a.txt", Join("dir", "b.txt")}
+	if got, err := zipPath.ZipEntries(); err != nil || !slices.Equal(got, want) {
+		t.Errorf("ZipEntries = %v, %v", got, err)
+	}
+	if got, err := tarPath.TarEntries(); err != nil || !slices.Equal(got, want) {
+		t.Errorf("TarEntries = %v, %v", got, err)
+	}
+}
+
+func TestExtractOne(t *testing.T) {
+	root := NewPath(t.TempDir())
+	files := map[string]string{"a.txt": "A", "dir/b.txt": "B"}
+	zipPath, tarPath := Join(root, "a.zip"), Join(root, "a.tar.gz")
+	writeTestZip(t, zipPath, files)
+	writeTestTarGz(t, tarPath, files)
+
+	for _, archive := range []Path{zipPath, tarPath} {
+		dst := Join(root, NewPath("out"+archive.Ext().String()))
+		dst.CreDir()
+		if err := archive.ExtractOne("a.txt", dst); err != nil {
+			t.Fatal(err)
+		}
+		if s := readTestFile(t, Join(dst, "a.txt")); s != "A" {
+			t.Errorf("%s: a.txt = %q", archive, s)
+		}
+		if names, _ := dst.Names(); len(names) != 1 {
+			t.Errorf("%s: extracted %v", archive, names)
+		}
+		if err := archive.ExtractOne("none.txt", dst); !errors.Is(err, os.ErrNotExist) {
+			t.Errorf("%s: ExtractOne(missing) = %v", archive, err)
+		}
+		if err := archive.ExtractOne(Join("..", "evil.txt"), dst); err == nil {
+			t.Errorf("%s: ExtractOne(outside) should fail", archive)
+		}
+	}
+}
@@ -0,0 +1,20 @@
+//go:build linux
+
+package path
+
+// アクセス日時の取得 (Linux)
+
+import (
+	"os"
+	"syscall"
+	"time"
+)
+
+// ファイル情報からアクセス日時を取得
+func accessTime(fi os.FileInfo) time.Time {
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return time.Time{}
+	}
+	return time.Unix(st.Atim.Unix())
+}
@@ -0,0 +1,22 @@
+//go:build linux
+
+package path
+
+import (
+	"os"
+	"testing"
+	"time"
+)
+
+func TestAccessTime(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	writeTestFile(t, p, "")
+	at := time.Now().Add(-48 * time.Hour).Truncate(time.Second)
+	if err := os.Chtimes(p.String(), at, time.Now()); err != nil {
+		t.Fatal(err)
+	}
+	fi, _ := os.Stat(p.String())
+	if got := accessTime(fi); !got.Equal(at) {
+		t.Errorf("accessTime = %v, want %v", got, at)
+	}
+}
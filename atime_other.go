@@ -0,0 +1,15 @@
+//go:build !linux
+
+package path
+
+// アクセス日時の取得 (Linux 以外)
+
+import (
+	"os"
+	"time"
+)
+
+// ファイル情報からアクセス日時を取得、未対応のためゼロ値を返す
+func accessTime(fi os.FileInfo) time.Time {
+	return time.Time{}
+}
@@ -0,0 +1,57 @@
+package path
+
+// ファイルの圧縮を扱う処理
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+
+	"github.com/dsnet/compress/bzip2"
+	"github.com/klauspost/compress/zstd"
+)
+
+// 拡張子ごとの圧縮処理
+var compressors = map[Ext]func(io.Writer) (io.WriteCloser, error){
+	".gz": func(w io.Writer) (io.WriteCloser, error) {
+		return gzip.NewWriter(w), nil
+	},
+	".zst": func(w io.Writer) (io.WriteCloser, error) {
+		return zstd.NewWriter(w)
+	},
+	".bz2": func(w io.Writer) (io.WriteCloser, error) {
+		return bzip2.NewWriter(w, nil)
+	},
+}
+
+// 対応している圧縮形式の拡張子
+func supportedCompressExts() string {
+	exts := make([]string, 0, len(compressors))
+	for ext := range compressors {
+		exts = append(exts, ext.String())
+	}
+	sort.Strings(exts)
+	return strings.Join(exts, ", ")
+}
+
+// ファイルを dst の拡張子 (.gz、.zst、.bz2) に応じた形式で圧縮して書き出す
+// 未対応の拡張子の場合は対応している拡張子を含むエラーを返す
+func (p Path) CompressTo(dst Path) error {
+	newWriter, ok := compressors[dst.Ext().Lower()]
+	if !ok {
+		return fmt.Errorf("unsupported compression extension %q (supported: %s)", dst.Ext(), supportedCompressExts())
+	}
+	return dst.writeAtomic(func(w io.Writer) error {
+		cw, err := newWriter(w)
+		if err != nil {
+			return err
+		}
+		if err := appendFileTo(cw, p); err != nil {
+			cw.Close()
+			return err
+		}
+		return cw.Close()
+	})
+}
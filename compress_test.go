@@ -0,0 +1,59 @@
+package path
+
+import (
+	"compress/gzip"
+	"io"
+	"os"
+	"strings"
+	"testing"
+
+	"github.com/dsnet/compress/bzip2"
+	"github.com/klauspost/compress/zstd"
+)
+
+func TestCompressTo(t *testing.T) {
+	root := NewPath(t.TempDir())
+	src := Join(root, "a.txt")
+	content := strings.Repeat("hello, world\n", 100)
+	writeTestFile(t, src, content)
+
+	readers := map[Path]func(io.Reader) (io.Reader, error){
+		"a.txt.gz": func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"a.txt.GZ": func(r io.Reader) (io.Reader, error) { return gzip.NewReader(r) },
+		"a.txt.zst": func(r io.Reader) (io.Reader, error) {
+			d, err := zstd.NewReader(r)
+			if err != nil {
+				return nil, err
+			}
+			return d.IOReadCloser(), nil
+		},
+		"a.txt.bz2": func(r io.Reader) (io.Reader, error) { return bzip2.NewReader(r, nil) },
+	}
+	for name, newReader := range readers {
+		dst := Join(root, name)
+		if err := src.CompressTo(dst); err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		f, err := os.Open(dst.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		r, err := newReader(f)
+		if err != nil {
+			t.Fatalf("%s: %v", name, err)
+		}
+		data, err := io.ReadAll(r)
+		f.Close()
+		if err != nil || string(data) != content {
+			t.Errorf("%s: round-trip mismatch, %v", name, err)
+		}
+	}
+
+	err := src.CompressTo(Join(root, "a.txt.xz"))
+	if err == nil || !strings.Contains(err.Error(), ".bz2, .gz, .zst") {
+		t.Errorf("CompressTo(.xz) = %v", err)
+	}
+	if _, err := os.Stat(Join(root, "a.txt.xz").String()); err == nil {
+		t.Error("CompressTo(.xz) created the file")
+	}
+}
@@ -0,0 +1,291 @@
+package path
+
+// ファイルをコピーする処理
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"strings"
+)
+
+// ファイルの内容とパーミッションを dst にコピー、dst が存在する場合は上書きする
+// 一時ファイル経由で書き込むため、失敗しても書きかけの dst は残らない。親ディレクトリは作成する
+func copyFile(src, dst Path) error {
+	in, err := src.FileOpen()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	return dst.writeAtomicPerm(fi.Mode().Perm(), func(w io.Writer) error {
+		_, err := io.Copy(w, in)
+		return err
+	})
+}
+
+// ファイルを dst にコピー、パーミッションも引き継ぐ。親ディレクトリは作成する
+// dst が既に存在する場合は os.ErrExist を返す
+func (p Path) CopyTo(dst Path) error {
+	if !p.IsFile() {
+		return os.ErrNotExist
+	}
+	if err := dst.DirName().CreDir(); err != nil {
+		return err
+	}
+	// 確認と書き込みの間に作成された dst を上書きしないよう、先に空のファイルで dst を確保する
+	f, err := os.OpenFile(string(dst), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0600)
+	if errors.Is(err, os.ErrExist) {
+		return fmt.Errorf("%s: %w", dst, os.ErrExist)
+	}
+	if err != nil {
+		return err
+	}
+	f.Close()
+	if err := copyFile(p, dst); err != nil {
+		os.Remove(string(dst))
+		return err
+	}
+	return nil
+}
+
+// ファイルを dst にコピー、パーミッションも引き継ぐ。親ディレクトリは作成する
+// dst が既に存在する場合は上書きする
+func (p Path) CopyToForce(dst Path) error {
+	return copyFile(p, dst)
+}
+
+// ファイルの内容、パーミッション、更新日時を dst にコピー、dst が存在する場合は上書きする
+// アクセス日時は取得できるプラットフォームでのみコピーし、所有者はコピーしない
+func (p Path) CopyMeta(dst Path) error {
+	fi, err := os.Stat(string(p))
+	if err != nil {
+		return err
+	}
+	if err := copyFile(p, dst); err != nil {
+		return err
+	}
+	// アクセス日時がゼロ値の場合は変更されない
+	return os.Chtimes(string(dst), accessTime(fi), fi.ModTime())
+}
+
+// src のパーミッションを p に適用
+func (p Path) CopyPermsFrom(src Path) error {
+	fi, err := os.Stat(string(src))
+	if err != nil {
+		return err
+	}
+	return os.Chmod(string(p), fi.Mode().Perm()|fi.Mode()&(os.ModeSetuid|os.ModeSetgid|os.ModeSticky))
+}
+
+// 2 つのパスを入れ替える、両方とも存在している必要がある
+// Linux では renameat2 の RENAME_EXCHANGE で原子的に入れ替え、
+// 未対応の環境では一時的な名前を経由した 3 回の名前変更で入れ替える (原子的ではない)
+func (p Path) Swap(b Path) error {
+	if !p.IsExist() {
+		return fmt.Errorf("%s: %w", p, os.ErrNotExist)
+	}
+	if !b.IsExist() {
+		return fmt.Errorf("%s: %w", b, os.ErrNotExist)
+	}
+	if err := exchange(p, b); err == nil || !errors.Is(err, errors.ErrUnsupported) {
+		return err
+	}
+	return swapByRename(p, b)
+}
+
+// 一時的な名前を経由して 2 つのパスを入れ替える
+func swapByRename(a, b Path) error {
+	tmp := a.DirName().RandomFile(Ext(".swap"))
+	if err := os.Rename(string(a), string(tmp)); err != nil {
+		return err
+	}
+	if err := os.Rename(string(b), string(a)); err != nil {
+		// 元に戻す
+		os.Rename(string(tmp), string(a))
+		return err
+	}
+	if err := os.Rename(string(tmp), string(b)); err != nil {
+		// b、a の順に元に戻し、戻せなかった場合はそのエラーも合わせて返す
+		errs := []error{err}
+		if rerr := os.Rename(string(a), string(b)); rerr != nil {
+			errs = append(errs, fmt.Errorf("rollback: %w", rerr))
+		} else if rerr := os.Rename(string(tmp), string(a)); rerr != nil {
+			errs = append(errs, fmt.Errorf("rollback: %w", rerr))
+		}
+		return errors.Join(errs...)
+	}
+	return nil
+}
+
+// dst に移動し、p を dst に更新する
+// 異なるファイルシステム間で名前を変更できない場合は、コピーして同期した後に元のファイルを削除する
+// コピーに失敗した場合は元のファイルを残す。ディレクトリは異なるファイルシステム間では移動できない
+func (p *Path) MoveTo(dst Path) error {
+	err := os.Rename(string(*p), string(dst))
+	if err != nil && isCrossDevice(err) && p.IsFile() {
+		err = copyFile(*p, dst)
+		if err == nil {
+			err = os.Remove(string(*p))
+		}
+	}
+	if err != nil {
+		return err
+	}
+	*p = dst
+	return nil
+}
+
+// fs.FS (embed.FS など) のファイルまたはディレクトリ src を dst にコピー、構造はそのまま保つ
+// ディレクトリは作成し、dst の外側を指すパスはエラーとする
+func CopyFS(fsys fs.FS, src string, dst Path) error {
+	return fs.WalkDir(fsys, src, func(name string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel := "."
+		if name != src {
+			rel = strings.TrimPrefix(name, src+"/")
+			if src == "." {
+				rel = name
+			}
+		}
+		out, err := safeJoin(dst, rel)
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return out.CreDir()
+		}
+
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		perm := fi.Mode().Perm()
+		if perm == 0 {
+			perm = 0644
+		}
+		in, err := fsys.Open(name)
+		if err != nil {
+			return err
+		}
+		defer in.Close()
+		return out.writeAtomicPerm(perm, func(w io.Writer) error {
+			_, err := io.Copy(w, in)
+			return err
+		})
+	})
+}
+
+// 進捗を通知しながら書き込む Writer
+type progressWriter struct {
+	w        io.Writer
+	copied   int64
+	total    int64
+	progress func(copied, total int64)
+}
+
+func (pw *progressWriter) Write(b []byte) (int, error) {
+	n, err := pw.w.Write(b)
+	pw.copied += int64(n)
+	if pw.progress != nil {
+		pw.progress(pw.copied, pw.total)
+	}
+	return n, err
+}
+
+// ファイルを dst に移動する
+// 異なるファイルシステム間ではコピーしてから元のファイルを削除し、コピー中は progress に進捗を通知する
+// コピーは dst.partial に書き込み、中断後に再度呼び出した場合は既存の dst.partial の続きからコピーする
+// ただし中断後に元のファイルのサイズか更新日時が変わった場合は、最初からコピーし直す
+// コピーしたサイズが元のファイルと一致した場合のみ元のファイルを削除する
+func (p Path) MoveResumable(dst Path, progress func(copied, total int64)) error {
+	err := os.Rename(string(p), string(dst))
+	if err == nil || !isCrossDevice(err) || !p.IsFile() {
+		return err
+	}
+
+	in, err := p.FileOpen()
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	fi, err := in.Stat()
+	if err != nil {
+		return err
+	}
+	if err := dst.DirName().CreDir(); err != nil {
+		return err
+	}
+	// 元のファイルが読み取り専用でも再開できるよう、コピー中は書き込み可能なパーミッションにする
+	partial := dst + ".partial"
+	out, err := os.OpenFile(string(partial), os.O_WRONLY|os.O_CREATE, 0600)
+	if err != nil {
+		return err
+	}
+
+	// dst.partial.meta に記録した元のファイルのサイズと更新日時が一致する場合のみ続きからコピー
+	// 記録がない場合や一致しない場合は最初からコピーし、今回の値を記録する
+	meta := partial + ".meta"
+	stamp := partialStamp(fi)
+	var offset int64
+	if data, rerr := os.ReadFile(string(meta)); rerr == nil && string(data) == stamp {
+		offset, err = out.Seek(0, io.SeekEnd)
+		if err == nil && offset > fi.Size() {
+			offset = 0
+			err = out.Truncate(0)
+		}
+	} else {
+		err = out.Truncate(0)
+		if err == nil {
+			err = os.WriteFile(string(meta), []byte(stamp), 0600)
+		}
+	}
+	if err == nil {
+		_, err = out.Seek(offset, io.SeekStart)
+	}
+	if err == nil {
+		_, err = in.Seek(offset, io.SeekStart)
+	}
+	if err == nil {
+		pw := &progressWriter{w: out, copied: offset, total: fi.Size(), progress: progress}
+		_, err = io.Copy(pw, in)
+	}
+	if err == nil {
+		err = out.Sync()
+	}
+	if cerr := out.Close(); err == nil {
+		err = cerr
+	}
+	if err != nil {
+		return err
+	}
+
+	// コピーが完了しているか確認
+	size, err := partial.Size()
+	if err != nil {
+		return err
+	}
+	if size != fi.Size() {
+		return fmt.Errorf("incomplete copy of %s: %d of %d bytes", p, size, fi.Size())
+	}
+	if err := os.Chmod(string(partial), fi.Mode().Perm()); err != nil {
+		return err
+	}
+	if err := os.Rename(string(partial), string(dst)); err != nil {
+		return err
+	}
+	os.Remove(string(meta))
+	return os.Remove(string(p))
+}
+
+// 再開の可否の判定に使う、元のファイルのサイズと更新日時
+func partialStamp(fi os.FileInfo) string {
+	return fmt.Sprintf("%d %d\n", fi.Size(), fi.ModTime().UnixNano())
+}
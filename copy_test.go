@@ -0,0 +1,263 @@
+package path
+
+import (
+	"errors"
+	"os"
+	"strings"
+	"testing"
+	"testing/fstest"
+	"time"
+)
+
+func TestCopyMeta(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "hello"})
+	src := Join(root, "a.txt")
+	mt := time.Now().Add(-24 * time.Hour).Truncate(time.Second)
+	os.Chtimes(src.String(), mt, mt)
+	os.Chmod(src.String(), 0600)
+
+	dst := Join(root, "b.txt")
+	if err := src.CopyMeta(dst); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Stat(dst.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !fi.ModTime().Equal(mt) || fi.Mode().Perm() != 0600 {
+		t.Errorf("CopyMeta mtime = %v, perm = %v", fi.ModTime(), fi.Mode().Perm())
+	}
+	if s := readTestFile(t, dst); s != "hello" {
+		t.Errorf("content = %q", s)
+	}
+}
+
+func TestCopyPermsFrom(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a": "", "b": ""})
+	os.Chmod(Join(root, "a").String(), 0750)
+	if err := Join(root, "b").CopyPermsFrom(Join(root, "a")); err != nil {
+		t.Fatal(err)
+	}
+	if fi, _ := os.Stat(Join(root, "b").String()); fi.Mode().Perm() != 0750 {
+		t.Errorf("perm = %v", fi.Mode().Perm())
+	}
+	if err := Join(root, "b").CopyPermsFrom(Join(root, "none")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("CopyPermsFrom(missing) = %v", err)
+	}
+}
+
+func TestSwap(t *testing.T) {
+	for name, swap := range map[string]func(a, b Path) error{
+		"Swap":         Path.Swap,
+		"swapByRename": swapByRename,
+	} {
+		t.Run(name, func(t *testing.T) {
+			root := buildTestTree(t, map[string]string{"a": "A", "b/x": "X"})
+			a, b := Join(root, "a"), Join(root, "b")
+			if err := swap(a, b); err != nil {
+				t.Fatal(err)
+			}
+			if !a.IsDir() || readTestFile(t, b) != "A" {
+				t.Error("paths were not swapped")
+			}
+			if names, _ := root.Names(); len(names) != 2 {
+				t.Errorf("temporary names left: %v", names)
+			}
+		})
+	}
+	root := buildTestTree(t, map[string]string{"a": "A"})
+	if err := Join(root, "a").Swap(Join(root, "none")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Swap(missing) = %v", err)
+	}
+}
+
+func TestCopyTo(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "hello", "exist.txt": "keep"})
+	src := Join(root, "a.txt")
+	os.Chmod(src.String(), 0640)
+
+	dst := Join(root, "sub", "b.txt")
+	if err := src.CopyTo(dst); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, dst); s != "hello" {
+		t.Errorf("content = %q", s)
+	}
+	if fi, _ := os.Stat(dst.String()); fi.Mode().Perm() != 0640 {
+		t.Errorf("perm = %v", fi.Mode().Perm())
+	}
+
+	exist := Join(root, "exist.txt")
+	err := src.CopyTo(exist)
+	if !errors.Is(err, os.ErrExist) || !strings.Contains(err.Error(), exist.String()) {
+		t.Errorf("CopyTo(existing) = %v", err)
+	}
+	if s := readTestFile(t, exist); s != "keep" {
+		t.Errorf("CopyTo overwrote the file: %q", s)
+	}
+	if err := src.CopyToForce(exist); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, exist); s != "hello" {
+		t.Errorf("CopyToForce content = %q", s)
+	}
+
+	if err := Join(root, "none").CopyTo(Join(root, "x")); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("CopyTo(missing) = %v", err)
+	}
+	if err := src.CopyTo(Join(exist, "child")); err == nil || errors.Is(err, os.ErrExist) {
+		t.Errorf("CopyTo(under a file) = %v", err)
+	}
+}
+
+func TestMoveTo(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "hello"})
+	p := Join(root, "a.txt")
+	dst := Join(root, "b.txt")
+	if err := p.MoveTo(dst); err != nil {
+		t.Fatal(err)
+	}
+	if p != dst || readTestFile(t, dst) != "hello" || Join(root, "a.txt").IsFile() {
+		t.Errorf("MoveTo: p = %s", p)
+	}
+	missing := Join(root, "none")
+	if err := missing.MoveTo(Join(root, "c")); err == nil || missing != Join(root, "none") {
+		t.Errorf("MoveTo(missing) = %v, p = %s", err, missing)
+	}
+}
+
+func TestCopyFS(t *testing.T) {
+	fsys := fstest.MapFS{
+		"static/index.html":   {Data: []byte("<html>")},
+		"static/css/site.css": {Data: []byte("body{}"), Mode: 0600},
+		"other.txt":           {Data: []byte("other")},
+	}
+	dst := Join(NewPath(t.TempDir()), "out")
+	if err := CopyFS(fsys, "static", dst); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, Join(dst, "index.html")); s != "<html>" {
+		t.Errorf("index.html = %q", s)
+	}
+	if s := readTestFile(t, Join(dst, "css", "site.css")); s != "body{}" {
+		t.Errorf("site.css = %q", s)
+	}
+	if fi, _ := os.Stat(Join(dst, "index.html").String()); fi.Mode().Perm() != 0644 {
+		t.Errorf("default perm = %v", fi.Mode().Perm())
+	}
+	if _, err := os.Stat(Join(dst, "other.txt").String()); err == nil {
+		t.Error("CopyFS copied outside src")
+	}
+	if err := CopyFS(fsys, "none", dst); err == nil {
+		t.Error("CopyFS(missing) should fail")
+	}
+}
+
+// t.TempDir() と異なるファイルシステム上のディレクトリを取得、見つからない場合はスキップ
+func crossDeviceDir(t *testing.T, src Path) Path {
+	t.Helper()
+	for _, dir := range []string{os.Getenv("PATH_TEST_XDEV_DIR"), "/dev/shm", "/run/user"} {
+		if dir == "" || !NewPath(dir).IsDir() {
+			continue
+		}
+		probe := NewPath(dir).RandomFile(".probe")
+		err := os.Rename(src.String(), probe.String())
+		if err == nil {
+			os.Rename(probe.String(), src.String())
+			continue
+		}
+		if isCrossDevice(err) {
+			d, err := os.MkdirTemp(dir, "path-test-")
+			if err != nil {
+				continue
+			}
+			t.Cleanup(func() { os.RemoveAll(d) })
+			return NewPath(d)
+		}
+	}
+	t.Skip("no directory on another file system")
+	return ""
+}
+
+func TestMoveResumable(t *testing.T) {
+	content := strings.Repeat("0123456789", 10000)
+	src := Join(NewPath(t.TempDir()), "big.bin")
+	writeTestFile(t, src, content)
+	os.Chmod(src.String(), 0444)
+	dst := Join(crossDeviceDir(t, src), "sub", "big.bin")
+
+	// 中断したコピーを再現
+	half := len(content) / 2
+	writeTestFile(t, dst+".partial", content[:half])
+	fi, _ := src.Stat()
+	writeTestFile(t, dst+".partial.meta", partialStamp(fi))
+
+	var first, last int64 = -1, 0
+	err := src.MoveResumable(dst, func(copied, total int64) {
+		if first < 0 {
+			first = copied
+		}
+		last = copied
+		if total != int64(len(content)) {
+			t.Errorf("total = %d", total)
+		}
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if first <= int64(half) || last != int64(len(content)) {
+		t.Errorf("progress started at %d and ended at %d, want resume from %d", first, last, half)
+	}
+	if s := readTestFile(t, dst); s != content {
+		t.Error("content mismatch after resume")
+	}
+	if fi, _ := os.Stat(dst.String()); fi.Mode().Perm() != 0444 {
+		t.Errorf("perm = %v", fi.Mode().Perm())
+	}
+	if src.IsExist() || (dst + ".partial").IsExist() || (dst + ".partial.meta").IsExist() {
+		t.Error("source or partial file left behind")
+	}
+}
+
+func TestMoveResumableStale(t *testing.T) {
+	content := strings.Repeat("0123456789", 10000)
+	half := len(content) / 2
+	for name, setup := range map[string]func(t *testing.T, src, dst Path){
+		// 記録がない
+		"no meta": func(*testing.T, Path, Path) {},
+		// 中断後に元のファイルが更新された
+		"modified": func(t *testing.T, src, dst Path) {
+			fi, _ := src.Stat()
+			writeTestFile(t, dst+".partial.meta", partialStamp(fi))
+			mt := fi.ModTime().Add(time.Minute)
+			os.Chtimes(src.String(), mt, mt)
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			src := Join(NewPath(t.TempDir()), "big.bin")
+			writeTestFile(t, src, content)
+			dst := Join(crossDeviceDir(t, src), "big.bin")
+			writeTestFile(t, dst+".partial", strings.Repeat("x", half))
+			setup(t, src, dst)
+
+			if err := src.MoveResumable(dst, nil); err != nil {
+				t.Fatal(err)
+			}
+			if s := readTestFile(t, dst); s != content {
+				t.Error("stale partial file was resumed")
+			}
+		})
+	}
+}
+
+func TestMoveResumableSameDevice(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "hello"})
+	dst := Join(root, "b.txt")
+	called := false
+	if err := Join(root, "a.txt").MoveResumable(dst, func(int64, int64) { called = true }); err != nil {
+		t.Fatal(err)
+	}
+	if called || readTestFile(t, dst) != "hello" {
+		t.Errorf("MoveResumable by rename: progress called = %v", called)
+	}
+}
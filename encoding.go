@@ -0,0 +1,146 @@
+package path
+
+// テキストファイルの文字コードを扱う処理
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/encoding/ianaindex"
+	"golang.org/x/text/transform"
+)
+
+// 文字コードの判定に読み込む最大バイト数
+const detectEncodingSize = 64 * 1024
+
+// BOM と文字コード名の対応、長いものから順に判定する
+var boms = []struct {
+	bom  []byte
+	name string
+}{
+	{[]byte{0x00, 0x00, 0xFE, 0xFF}, "UTF-32BE"},
+	{[]byte{0xFF, 0xFE, 0x00, 0x00}, "UTF-32LE"},
+	{utf8BOM, "UTF-8"},
+	{[]byte{0xFE, 0xFF}, "UTF-16BE"},
+	{[]byte{0xFF, 0xFE}, "UTF-16LE"},
+}
+
+// ファイルの文字コードを推定して名前を返す
+// BOM があればそれに従い、なければ先頭部分が UTF-8、EUC-JP、Shift_JIS として正しいかを順に判定する
+// いずれにも当てはまらない場合は "UTF-8" を返す
+func (p Path) DetectEncoding() (string, error) {
+	f, err := p.FileOpen()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	data, err := io.ReadAll(io.LimitReader(f, detectEncodingSize))
+	if err != nil {
+		return "", err
+	}
+
+	for _, b := range boms {
+		if bytes.HasPrefix(data, b.bom) {
+			return b.name, nil
+		}
+	}
+	switch {
+	case isUTF8(data):
+		return "UTF-8", nil
+	case isEUCJP(data):
+		return "EUC-JP", nil
+	case isShiftJIS(data):
+		return "Shift_JIS", nil
+	}
+	return "UTF-8", nil
+}
+
+// UTF-8 として正しいか判定、末尾で途切れた文字は許容する
+func isUTF8(data []byte) bool {
+	for len(data) > 0 {
+		r, size := utf8.DecodeRune(data)
+		if r == utf8.RuneError && size <= 1 {
+			return !utf8.FullRune(data)
+		}
+		data = data[size:]
+	}
+	return true
+}
+
+// EUC-JP として正しいか判定、末尾で途切れた文字は許容する
+func isEUCJP(data []byte) bool {
+	// 2 バイト目以降の範囲
+	trail := func(b byte) bool { return 0xA1 <= b && b <= 0xFE }
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c < 0x80:
+		case c == 0x8E:
+			// 半角カナ
+			if i+1 < len(data) && !(0xA1 <= data[i+1] && data[i+1] <= 0xDF) {
+				return false
+			}
+			i++
+		case c == 0x8F:
+			// 補助漢字
+			if i+1 < len(data) && !trail(data[i+1]) || i+2 < len(data) && !trail(data[i+2]) {
+				return false
+			}
+			i += 2
+		case trail(c):
+			if i+1 < len(data) && !trail(data[i+1]) {
+				return false
+			}
+			i++
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// Shift_JIS として正しいか判定、末尾で途切れた文字は許容する
+func isShiftJIS(data []byte) bool {
+	for i := 0; i < len(data); i++ {
+		c := data[i]
+		switch {
+		case c < 0x80, 0xA1 <= c && c <= 0xDF:
+			// ASCII、半角カナ
+		case 0x81 <= c && c <= 0x9F, 0xE0 <= c && c <= 0xFC:
+			if i+1 < len(data) {
+				t := data[i+1]
+				if !(0x40 <= t && t <= 0x7E || 0x80 <= t && t <= 0xFC) {
+					return false
+				}
+			}
+			i++
+		default:
+			return false
+		}
+	}
+	return true
+}
+
+// fromEncoding の文字コードのファイルを UTF-8 に変換して dst に書き出す、親ディレクトリは作成する
+// 文字コード名は "Shift_JIS"、"EUC-JP"、"UTF-16LE" などの IANA または WHATWG の名前で指定する
+func (p Path) ToUTF8(dst Path, fromEncoding string) error {
+	enc, err := htmlindex.Get(fromEncoding)
+	if err != nil {
+		enc, err = ianaindex.IANA.Encoding(fromEncoding)
+	}
+	if err != nil || enc == nil {
+		return fmt.Errorf("unknown encoding: %q", fromEncoding)
+	}
+	src, err := p.FileOpen()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+	return dst.writeAtomic(func(w io.Writer) error {
+		_, err := io.Copy(w, transform.NewReader(src, enc.NewDecoder()))
+		return err
+	})
+}
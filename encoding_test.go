@@ -0,0 +1,52 @@
+package path
+
+import (
+	"testing"
+)
+
+func TestDetectEncoding(t *testing.T) {
+	tests := []struct {
+		content string
+		want    string
+	}{
+		{"\xFF\xFEh\x00i\x00", "UTF-16LE"},
+		{"\xFE\xFF\x00h\x00i", "UTF-16BE"},
+		{"\xEF\xBB\xBFabc", "UTF-8"},
+		{"日本語", "UTF-8"},
+		{"\xC6\xFC\xCB\xDC\xB8\xEC", "EUC-JP"},
+		{"\x93\xFA\x96\x7B\x8C\xEA", "Shift_JIS"},
+		{"", "UTF-8"},
+	}
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	for _, tt := range tests {
+		writeTestFile(t, p, tt.content)
+		if got, err := p.DetectEncoding(); err != nil || got != tt.want {
+			t.Errorf("DetectEncoding(%q) = %q, %v, want %q", tt.content, got, err, tt.want)
+		}
+	}
+}
+
+func TestToUTF8(t *testing.T) {
+	root := NewPath(t.TempDir())
+	src := Join(root, "sjis.txt")
+	writeTestFile(t, src, "\x93\xFA\x96\x7B\x8C\xEA")
+	dst := Join(root, "out", "utf8.txt")
+	if err := src.ToUTF8(dst, "Shift_JIS"); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, dst); s != "日本語" {
+		t.Errorf("ToUTF8(Shift_JIS) = %q", s)
+	}
+
+	writeTestFile(t, src, "\xC6\xFC\xCB\xDC\xB8\xEC")
+	if err := src.ToUTF8(dst, "EUC-JP"); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, dst); s != "日本語" {
+		t.Errorf("ToUTF8(EUC-JP) = %q", s)
+	}
+
+	if err := src.ToUTF8(dst, "no-such-encoding"); err == nil {
+		t.Error("ToUTF8(unknown) should fail")
+	}
+}
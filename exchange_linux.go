@@ -0,0 +1,21 @@
+//go:build linux
+
+package path
+
+// パスの原子的な入れ替え (Linux)
+
+import (
+	"errors"
+
+	"golang.org/x/sys/unix"
+)
+
+// renameat2 の RENAME_EXCHANGE で 2 つのパスを入れ替える
+// カーネルやファイルシステムが未対応の場合は errors.ErrUnsupported を返す
+func exchange(a, b Path) error {
+	err := unix.Renameat2(unix.AT_FDCWD, string(a), unix.AT_FDCWD, string(b), unix.RENAME_EXCHANGE)
+	if errors.Is(err, unix.ENOSYS) || errors.Is(err, unix.EINVAL) {
+		return errors.ErrUnsupported
+	}
+	return err
+}
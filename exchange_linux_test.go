@@ -0,0 +1,26 @@
+//go:build linux
+
+package path
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestExchange(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a": "A", "b": "B"})
+	a, b := Join(root, "a"), Join(root, "b")
+	err := exchange(a, b)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil {
+		t.Fatal(err)
+	}
+	if readTestFile(t, a) != "B" || readTestFile(t, b) != "A" {
+		t.Error("paths were not exchanged")
+	}
+	if err := exchange(a, Join(root, "none")); err == nil || errors.Is(err, errors.ErrUnsupported) {
+		t.Errorf("exchange(missing) = %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+//go:build !linux
+
+package path
+
+// パスの原子的な入れ替え (Linux 以外)
+
+import (
+	"errors"
+)
+
+// 2 つのパスを原子的に入れ替える、未対応のため errors.ErrUnsupported を返す
+func exchange(a, b Path) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,15 @@
+//go:build !unix
+
+package path
+
+// 名前付きパイプの作成 (Unix 以外)
+
+import (
+	"errors"
+	"os"
+)
+
+// 名前付きパイプを作成、Unix 以外では未対応
+func (p Path) Mkfifo(mode os.FileMode) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,27 @@
+//go:build unix
+
+package path
+
+// 名前付きパイプの作成 (Unix)
+
+import (
+	"fmt"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// 名前付きパイプを作成、親ディレクトリも作成する
+// 既に存在する場合はエラー
+func (p Path) Mkfifo(mode os.FileMode) error {
+	if p.IsExist() {
+		return fmt.Errorf("%s: %w", p, os.ErrExist)
+	}
+	if err := p.DirName().CreDir(); err != nil {
+		return err
+	}
+	if err := unix.Mkfifo(string(p), uint32(mode.Perm())); err != nil {
+		return &os.PathError{Op: "mkfifo", Path: string(p), Err: err}
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+//go:build unix
+
+package path
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestMkfifo(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "sub", "pipe")
+	if err := p.Mkfifo(0600); err != nil {
+		t.Fatal(err)
+	}
+	fi, err := os.Lstat(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	if fi.Mode().Type() != os.ModeNamedPipe {
+		t.Errorf("mode = %v", fi.Mode())
+	}
+	if err := p.Mkfifo(0600); !errors.Is(err, os.ErrExist) {
+		t.Errorf("Mkfifo(existing) = %v", err)
+	}
+}
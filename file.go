@@ -0,0 +1,937 @@
+package path
+
+// ファイルの内容を読み書きする処理
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"iter"
+	"math"
+	"os"
+	"regexp"
+	"strings"
+)
+
+// ファイルサイズが上限を超えている場合のエラー
+var ErrTooLarge = errors.New("file too large")
+
+// ファイルの内容をすべて読み込む
+func (p Path) ReadBytes() ([]byte, error) {
+	data, err := os.ReadFile(string(p))
+	if err != nil {
+		return nil, fmt.Errorf("read %s: %w", p, err)
+	}
+	return data, nil
+}
+
+// ファイルの内容をすべて文字列として読み込む
+func (p Path) ReadString() (string, error) {
+	data, err := p.ReadBytes()
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+// ファイルに data を書き込む、親ディレクトリは作成する
+// ファイルが存在しない場合は perm で作成し、存在する場合は内容を置き換える
+func (p Path) WriteBytes(data []byte, perm os.FileMode) error {
+	if err := p.DirName().CreDir(); err != nil {
+		return err
+	}
+	return os.WriteFile(string(p), data, perm)
+}
+
+// ファイルに文字列を書き込む、親ディレクトリは作成する
+// ファイルが存在しない場合は perm で作成し、存在する場合は内容を置き換える
+func (p Path) WriteString(s string, perm os.FileMode) error {
+	return p.WriteBytes([]byte(s), perm)
+}
+
+// ファイルの末尾に文字列を追記する
+// ファイルが存在しない場合は 0644 で作成する。親ディレクトリは作成しない
+func (p Path) AppendString(s string) error {
+	f, err := os.OpenFile(string(p), os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.WriteString(s); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ファイルを行ごとに読み込む、行末の "\r" は除く
+// ファイルが改行で終わる場合、末尾の空の要素は含めない
+func (p Path) ReadLines() ([]string, error) {
+	s, err := p.ReadString()
+	if err != nil {
+		return nil, err
+	}
+	if s == "" {
+		return []string{}, nil
+	}
+	lines := strings.Split(strings.TrimSuffix(s, "\n"), "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSuffix(line, "\r")
+	}
+	return lines, nil
+}
+
+// 各行を改行で連結し、末尾にも改行を付けてファイルに書き込む、親ディレクトリは作成する
+func (p Path) WriteLines(lines []string, perm os.FileMode) error {
+	var b strings.Builder
+	for _, line := range lines {
+		b.WriteString(line)
+		b.WriteByte('\n')
+	}
+	return p.WriteString(b.String(), perm)
+}
+
+// ファイルの内容を最大 max バイトまで読み込む
+// ファイルが max バイトを超える場合は ErrTooLarge を返す
+func (p Path) ReadAllLimit(max int64) ([]byte, error) {
+	f, err := p.FileOpen()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	// サイズが分かっている場合は読み込む前に判定
+	fi, err := f.Stat()
+	if err != nil {
+		return nil, err
+	}
+	if fi.Mode().IsRegular() && fi.Size() > max {
+		return nil, ErrTooLarge
+	}
+
+	// 読み込み中にファイルが伸びた場合に備え、1 バイト余分に読んで判定
+	// max が int64 の最大値の場合は 1 バイト足すと桁あふれするため制限しない
+	var r io.Reader = f
+	if max < math.MaxInt64 {
+		r = io.LimitReader(f, max+1)
+	}
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if int64(len(data)) > max {
+		return nil, ErrTooLarge
+	}
+	return data, nil
+}
+
+// ファイルを linesPerFile 行ごとに分割し、dst ディレクトリに連番付きのファイルとして書き出す
+// 最後のファイルには残りの行が入る。作成したファイルを返す
+func (p Path) SplitByLines(linesPerFile int, dst Path) (Entries, error) {
+	if linesPerFile <= 0 {
+		return nil, fmt.Errorf("invalid lines per file: %d", linesPerFile)
+	}
+	src, err := p.FileOpen()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+	if err := dst.CreDir(); err != nil {
+		return nil, err
+	}
+
+	entries := Entries{}
+	var out *os.File
+	// 作成中のファイルを閉じる
+	closeOut := func() error {
+		if out == nil {
+			return nil
+		}
+		err := out.Close()
+		out = nil
+		return err
+	}
+	defer closeOut()
+
+	r := bufio.NewReader(src)
+	for lines := 0; ; lines++ {
+		line, readErr := r.ReadBytes('\n')
+		if len(line) > 0 {
+			if lines%linesPerFile == 0 {
+				// 新しいファイルを作成
+				if err := closeOut(); err != nil {
+					return nil, err
+				}
+				name := fmt.Sprintf("%s_%03d%s", p.FileNameWithoutExt(), len(entries)+1, p.Ext())
+				chunk := Join(dst, NewPath(name))
+				out, err = os.Create(string(chunk))
+				if err != nil {
+					return nil, err
+				}
+				entries = append(entries, chunk)
+			}
+			if _, err := out.Write(line); err != nil {
+				return nil, err
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+		if readErr != nil {
+			return nil, readErr
+		}
+	}
+	if err := closeOut(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+// Entries のファイルを順番に連結して dst に書き出す
+// ファイルでない要素が含まれる場合はエラー
+func (e Entries) Concatenate(dst Path) error {
+	return e.ConcatenateWithSeparator(dst, nil)
+}
+
+// Entries のファイルを順番に連結して dst に書き出す。ファイルの間に sep を挟む
+// 一時ファイルに書き込んでから置き換えるため、dst が Entries に含まれていてもよい
+// ファイルでない要素が含まれる場合や失敗した場合は、dst を変更せずにエラー
+func (e Entries) ConcatenateWithSeparator(dst Path, sep []byte) error {
+	for _, entry := range e {
+		if !entry.IsFile() {
+			return fmt.Errorf("%s: %w", entry, os.ErrInvalid)
+		}
+	}
+	return dst.writeAtomic(func(w io.Writer) error {
+		for i, entry := range e {
+			if i > 0 && len(sep) > 0 {
+				if _, err := w.Write(sep); err != nil {
+					return err
+				}
+			}
+			if err := appendFileTo(w, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+// ファイルの内容を w に書き出す
+func appendFileTo(w io.Writer, p Path) error {
+	f, err := p.FileOpen()
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(w, f)
+	return err
+}
+
+// ファイルが存在しない場合のみ data を書き込む
+// 既に存在する場合は os.ErrExist を返す。親ディレクトリは作成する
+func (p Path) WriteNew(data []byte) error {
+	if err := p.DirName().CreDir(); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(string(p), os.O_WRONLY|os.O_CREATE|os.O_EXCL, 0666)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// 同じディレクトリに一時ファイルを作成
+func (p Path) createTempSibling() (*os.File, error) {
+	return os.CreateTemp(string(p.DirName()), "."+p.FileName().String()+".*.tmp")
+}
+
+// 一時ファイルに書き込んでから置き換えることで、ファイルを原子的に書き込む
+// 既存ファイルのパーミッションは引き継ぎ、新規の場合は 0644 とする
+// 失敗した場合は一時ファイルを削除し、元のファイルは変更しない
+func (p Path) writeAtomic(write func(w io.Writer) error) error {
+	return p.writeAtomicPerm(p.permOr(0644), write)
+}
+
+// パーミッションを指定して、ファイルを原子的に書き込む
+func (p Path) writeAtomicPerm(perm os.FileMode, write func(w io.Writer) error) error {
+	if err := p.DirName().CreDir(); err != nil {
+		return err
+	}
+	f, err := p.createTempSibling()
+	if err != nil {
+		return err
+	}
+	tmp := f.Name()
+	err = write(f)
+	if err == nil {
+		err = f.Chmod(perm)
+	}
+	if err == nil {
+		err = f.Sync()
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = os.Rename(tmp, string(p))
+	}
+	if err != nil {
+		os.Remove(tmp)
+		return err
+	}
+	return nil
+}
+
+// ファイルのパーミッションを取得、存在しない場合は def を返す
+func (p Path) permOr(def os.FileMode) os.FileMode {
+	fi, err := os.Stat(string(p))
+	if err != nil {
+		return def
+	}
+	return fi.Mode().Perm()
+}
+
+// ファイルを一時ファイルにコピーして edit で編集し、成功した場合のみ元のファイルと置き換える
+// edit がエラーを返した場合は一時ファイルを破棄し、元のファイルは変更しない
+func (p Path) EditAtomic(edit func(tmp Path) error) error {
+	src, err := p.FileOpen()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	f, err := p.createTempSibling()
+	if err != nil {
+		return err
+	}
+	tmp := NewPath(f.Name())
+	_, err = io.Copy(f, src)
+	if err == nil {
+		err = f.Chmod(p.permOr(0644))
+	}
+	if cerr := f.Close(); err == nil {
+		err = cerr
+	}
+	if err == nil {
+		err = edit(tmp)
+	}
+	if err == nil {
+		err = os.Rename(string(tmp), string(p))
+	}
+	if err != nil {
+		os.Remove(string(tmp))
+		return err
+	}
+	return nil
+}
+
+// ファイルの先頭に data を挿入する
+// 一時ファイル経由で書き換えるため、ファイル全体をメモリに読み込まない
+// ファイルが存在しない場合は data のみのファイルを作成する
+func (p Path) Prepend(data []byte) error {
+	if !p.IsFile() {
+		if p.IsExist() {
+			return fmt.Errorf("%s: %w", p, os.ErrInvalid)
+		}
+		return p.writeAtomic(func(w io.Writer) error {
+			_, err := w.Write(data)
+			return err
+		})
+	}
+	return p.writeAtomic(func(w io.Writer) error {
+		if _, err := w.Write(data); err != nil {
+			return err
+		}
+		return appendFileTo(w, p)
+	})
+}
+
+// 1 始まりの lineNum 行目の前に text を 1 行として挿入し、原子的に書き換える
+// lineNum が行数を超える場合は末尾に追加し、1 未満の場合はエラー
+func (p Path) InsertAtLine(lineNum int, text string) error {
+	if lineNum < 1 {
+		return fmt.Errorf("invalid line number: %d", lineNum)
+	}
+	src, err := p.FileOpen()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	return p.writeAtomic(func(w io.Writer) error {
+		r := bufio.NewReader(src)
+		inserted := false
+		last := ""
+		for n := 1; ; n++ {
+			line, err := r.ReadString('\n')
+			if line != "" {
+				if n == lineNum {
+					if _, err := io.WriteString(w, text+"\n"); err != nil {
+						return err
+					}
+					inserted = true
+				}
+				if _, err := io.WriteString(w, line); err != nil {
+					return err
+				}
+				last = line
+			}
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return err
+			}
+		}
+		if inserted {
+			return nil
+		}
+		// 末尾に追加、最終行に改行がない場合は補う
+		if last != "" && !strings.HasSuffix(last, "\n") {
+			text = "\n" + text
+		}
+		_, err := io.WriteString(w, text+"\n")
+		return err
+	})
+}
+
+// 1 始まりの startLine 行目から count 行を取得、改行文字は除く
+// startLine より前の行は保持せずに読み飛ばす。範囲外の場合は空のスライスを返す
+func (p Path) ReadLinesRange(startLine, count int) ([]string, error) {
+	f, err := p.FileOpen()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	lines := []string{}
+	if startLine < 1 || count < 1 {
+		return lines, nil
+	}
+	r := bufio.NewReader(f)
+	for n := 1; len(lines) < count; n++ {
+		line, err := r.ReadString('\n')
+		if line != "" && n >= startLine {
+			lines = append(lines, strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r"))
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, err
+		}
+	}
+	return lines, nil
+}
+
+// 指定サイズのファイルを作成、親ディレクトリも作成する
+// 対応するファイルシステムではスパースファイルとなり、ゼロの書き込みは行わない
+// 既にファイルが存在する場合はエラー
+func (p Path) CreateSized(size int64) error {
+	f, err := p.CreFile()
+	if err != nil {
+		return err
+	}
+	if err := f.Truncate(size); err != nil {
+		f.Close()
+		os.Remove(string(p))
+		return err
+	}
+	return f.Close()
+}
+
+// 指定サイズの領域を実際にディスク上に確保したファイルを作成、親ディレクトリも作成する
+// Linux では fallocate を使い、それ以外や fallocate 未対応のファイルシステムではゼロを書き込む
+// 既存ファイルの内容は変更せず、足りない分のみ確保する
+func (p Path) Allocate(size int64) error {
+	if err := p.DirName().CreDir(); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(string(p), os.O_WRONLY|os.O_CREATE, 0666)
+	if err != nil {
+		return err
+	}
+	if err := allocate(f, size); err != nil {
+		f.Close()
+		return err
+	}
+	return f.Close()
+}
+
+// ファイルの末尾から size までゼロを書き込む
+func writeZeros(f *os.File, size int64) error {
+	fi, err := f.Stat()
+	if err != nil {
+		return err
+	}
+	if fi.Size() >= size {
+		return nil
+	}
+	if _, err := f.Seek(fi.Size(), io.SeekStart); err != nil {
+		return err
+	}
+	_, err = io.CopyN(f, zeroReader{}, size-fi.Size())
+	return err
+}
+
+// ゼロを返し続ける io.Reader
+type zeroReader struct{}
+
+func (zeroReader) Read(b []byte) (int, error) {
+	clear(b)
+	return len(b), nil
+}
+
+// CSV ファイルのレコードを 1 行ずつ返すイテレータを取得
+// ファイルは反復の開始時に開き、反復の終了時 (途中で抜けた場合も含む) に閉じる
+// 行ごとの解析エラーは 2 番目の値で返して次の行へ進み、読み込みエラーの場合はそこで終了する
+func (p Path) CSVRows() (iter.Seq2[[]string, error], error) {
+	// ファイルでない場合はエラー
+	if !p.IsFile() {
+		return nil, os.ErrNotExist
+	}
+	return func(yield func([]string, error) bool) {
+		f, err := os.Open(string(p))
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer f.Close()
+
+		r := csv.NewReader(f)
+		for {
+			record, err := r.Read()
+			if err == io.EOF {
+				return
+			}
+			var parseErr *csv.ParseError
+			if err != nil && !errors.As(err, &parseErr) {
+				yield(nil, err)
+				return
+			}
+			if !yield(record, err) {
+				return
+			}
+		}
+	}, nil
+}
+
+// r の内容を一時ファイルに書き込み、同期してから置き換えることで原子的に書き込む
+// 書き込んだバイト数を返す。失敗した場合は一時ファイルを削除し、元のファイルは変更しない
+func (p Path) WriteReaderAtomic(r io.Reader) (int64, error) {
+	var n int64
+	err := p.writeAtomic(func(w io.Writer) error {
+		var err error
+		n, err = io.Copy(w, r)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return n, nil
+}
+
+// Entries のファイルをすべて開き、読み込み用のハンドルとそれらをまとめて閉じる関数を返す
+// いずれかのファイルを開けない場合は、既に開いたファイルを閉じてからエラーを返す
+func (e Entries) OpenAll() ([]io.ReadCloser, func() error, error) {
+	files := make([]io.ReadCloser, 0, len(e))
+	closeAll := func() error {
+		var errs []error
+		for _, f := range files {
+			if err := f.Close(); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return errors.Join(errs...)
+	}
+	for _, entry := range e {
+		f, err := entry.FileOpen()
+		if err != nil {
+			closeAll()
+			return nil, nil, fmt.Errorf("%s: %w", entry, err)
+		}
+		files = append(files, f)
+	}
+	return files, closeAll, nil
+}
+
+// ファイルに完全に一致する行がない場合のみ、line を 1 行として末尾に追加する
+// 追加した場合は true を返す。ファイルが存在しない場合は作成する
+func (p Path) AppendLineUnique(line string) (bool, error) {
+	f, err := os.OpenFile(string(p), os.O_RDWR|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	r := bufio.NewReader(f)
+	last := ""
+	for {
+		l, err := r.ReadString('\n')
+		if strings.TrimSuffix(strings.TrimSuffix(l, "\n"), "\r") == line && l != "" {
+			return false, nil
+		}
+		if l != "" {
+			last = l
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return false, err
+		}
+	}
+
+	// 最終行に改行がない場合は補う
+	if last != "" && !strings.HasSuffix(last, "\n") {
+		line = "\n" + line
+	}
+	if _, err := f.WriteString(line + "\n"); err != nil {
+		return false, err
+	}
+	return true, f.Close()
+}
+
+// SedReplace で変更がなく、書き換えを取りやめることを示す
+var errUnchanged = errors.New("unchanged")
+
+// 各行に正規表現の置換を適用し、原子的に書き換える
+// repl では $1 などのサブマッチ参照を使える。変更された行数を返す
+// 変更された行がない場合はファイルを置き換えないため、更新日時も変わらない
+func (p Path) SedReplace(re *regexp.Regexp, repl string) (int, error) {
+	src, err := p.FileOpen()
+	if err != nil {
+		return 0, err
+	}
+	defer src.Close()
+
+	changed := 0
+	err = p.writeAtomic(func(w io.Writer) error {
+		r := bufio.NewReader(src)
+		for {
+			line, err := r.ReadString('\n')
+			if line != "" {
+				// 改行文字は置換の対象外
+				body := strings.TrimSuffix(line, "\n")
+				eol := line[len(body):]
+				if strings.HasSuffix(body, "\r") {
+					body, eol = body[:len(body)-1], "\r"+eol
+				}
+				replaced := re.ReplaceAllString(body, repl)
+				if replaced != body {
+					changed++
+				}
+				if _, err := io.WriteString(w, replaced+eol); err != nil {
+					return err
+				}
+			}
+			if err == io.EOF {
+				if changed == 0 {
+					return errUnchanged
+				}
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	})
+	if err != nil && err != errUnchanged {
+		return 0, err
+	}
+	return changed, nil
+}
+
+// UTF-8 の BOM
+var utf8BOM = []byte{0xEF, 0xBB, 0xBF}
+
+// 先頭の UTF-8 の BOM を取り除く
+func StripBOM(b []byte) []byte {
+	return bytes.TrimPrefix(b, utf8BOM)
+}
+
+// ファイルを文字列として読み込み、先頭の UTF-8 の BOM を取り除く
+func (p Path) ReadTextNoBOM() (string, error) {
+	data, err := os.ReadFile(string(p))
+	if err != nil {
+		return "", err
+	}
+	return string(StripBOM(data)), nil
+}
+
+// 内容が s と異なる場合のみファイルを原子的に書き込み、書き込んだかどうかを返す
+// 内容が同じ場合は書き込まないため、更新日時も変わらない。ファイルが存在しない場合は常に書き込む
+func (p Path) WriteTextIfChanged(s string) (bool, error) {
+	data, err := os.ReadFile(string(p))
+	if err == nil && string(data) == s {
+		return false, nil
+	}
+	if err != nil && !errors.Is(err, os.ErrNotExist) {
+		return false, err
+	}
+	err = p.writeAtomic(func(w io.Writer) error {
+		_, err := io.WriteString(w, s)
+		return err
+	})
+	if err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// 排他ロックを保持した追記用の Writer
+type lockedWriter struct {
+	f *os.File
+}
+
+func (w *lockedWriter) Write(b []byte) (int, error) {
+	return w.f.Write(b)
+}
+
+// ロックを解除してファイルを閉じる
+func (w *lockedWriter) Close() error {
+	err := unlockFile(w.f)
+	if cerr := w.f.Close(); err == nil {
+		err = cerr
+	}
+	return err
+}
+
+// ファイルを追記モードで開き、排他ロックを取得した Writer を返す
+// ロックは Close で解除される。ロックは勧告ロックのため、ロックを使う他の書き込みとの間でのみ有効
+// ファイルが存在しない場合は作成する
+func (p Path) AppendWriter() (io.WriteCloser, error) {
+	f, err := os.OpenFile(string(p), os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0666)
+	if err != nil {
+		return nil, err
+	}
+	if err := lockFile(f); err != nil {
+		f.Close()
+		return nil, err
+	}
+	return &lockedWriter{f: f}, nil
+}
+
+// ファイルを size バイトずつ返すイテレータを取得、最後のブロックは size より短い場合がある
+// 返すスライスはブロックごとに新しく確保するため、反復後も保持してよい
+// ファイルは反復の開始時に開き、反復の終了時 (途中で抜けた場合も含む) に閉じる
+func (p Path) Chunks(size int) (iter.Seq2[[]byte, error], error) {
+	if size <= 0 {
+		return nil, fmt.Errorf("invalid chunk size: %d", size)
+	}
+	// ファイルでない場合はエラー
+	if !p.IsFile() {
+		return nil, os.ErrNotExist
+	}
+	return func(yield func([]byte, error) bool) {
+		f, err := os.Open(string(p))
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer f.Close()
+
+		for {
+			buf := make([]byte, size)
+			n, err := io.ReadFull(f, buf)
+			if n > 0 && !yield(buf[:n], nil) {
+				return
+			}
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				return
+			}
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+		}
+	}, nil
+}
+
+// ファイルを読み込み、delim で区切った文字列を取得
+// ファイルが delim で終わる場合、末尾の空の要素は含めない
+func (p Path) ReadSplit(delim byte) ([]string, error) {
+	data, err := os.ReadFile(string(p))
+	if err != nil {
+		return nil, err
+	}
+	if len(data) == 0 {
+		return []string{}, nil
+	}
+	sep := string([]byte{delim})
+	return strings.Split(strings.TrimSuffix(string(data), sep), sep), nil
+}
+
+// Entries を 1 行に 1 つずつファイルに原子的に書き込む
+func (e Entries) WriteList(p Path) error {
+	return e.writeList(p, '\n')
+}
+
+// Entries を NUL 区切りでファイルに原子的に書き込む、改行を含むパス向け
+func (e Entries) WriteListNull(p Path) error {
+	return e.writeList(p, 0)
+}
+
+// Entries を delim 区切りでファイルに原子的に書き込む
+func (e Entries) writeList(p Path, delim byte) error {
+	return p.writeAtomic(func(w io.Writer) error {
+		bw := bufio.NewWriter(w)
+		for _, entry := range e {
+			bw.WriteString(entry.String())
+			bw.WriteByte(delim)
+		}
+		return bw.Flush()
+	})
+}
+
+// 1 行に 1 つずつパスを書いたファイルを読み込む、空行は除く
+func ReadList(p Path) (Entries, error) {
+	lines, err := p.ReadSplit('\n')
+	if err != nil {
+		return nil, err
+	}
+	entries := Entries{}
+	for _, line := range lines {
+		if line = strings.TrimSuffix(line, "\r"); line != "" {
+			entries = append(entries, NewPath(line))
+		}
+	}
+	return entries, nil
+}
+
+// NUL 区切りでパスを書いたファイルを読み込む
+func ReadListNull(p Path) (Entries, error) {
+	data, err := os.ReadFile(string(p))
+	if err != nil {
+		return nil, err
+	}
+	return SplitNull(data), nil
+}
+
+// ファイルの改行コードを判定し、"\n"、"\r\n"、混在している場合は "mixed" を返す
+// 改行がない場合は "\n" を返す
+func (p Path) DetectLineEnding() (string, error) {
+	f, err := p.FileOpen()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+
+	lf, crlf := false, false
+	r := bufio.NewReader(f)
+	for {
+		line, err := r.ReadString('\n')
+		if strings.HasSuffix(line, "\r\n") {
+			crlf = true
+		} else if strings.HasSuffix(line, "\n") {
+			lf = true
+		}
+		if lf && crlf {
+			return "mixed", nil
+		}
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+	}
+	if crlf {
+		return "\r\n", nil
+	}
+	return "\n", nil
+}
+
+// ファイルの改行コードを to ("\n" または "\r\n") に統一し、原子的に書き換える
+func (p Path) NormalizeLineEndings(to string) error {
+	if to != "\n" && to != "\r\n" {
+		return fmt.Errorf("invalid line ending: %q", to)
+	}
+	src, err := p.FileOpen()
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	return p.writeAtomic(func(w io.Writer) error {
+		r := bufio.NewReader(src)
+		for {
+			line, err := r.ReadString('\n')
+			if strings.HasSuffix(line, "\n") {
+				line = strings.TrimSuffix(strings.TrimSuffix(line, "\n"), "\r") + to
+			}
+			if _, err := io.WriteString(w, line); err != nil {
+				return err
+			}
+			if err == io.EOF {
+				return nil
+			}
+			if err != nil {
+				return err
+			}
+		}
+	})
+}
+
+// ファイルの内容をランダムなデータで passes 回上書きしてから削除する
+// 上書きのたびにディスクへ同期する。SSD やコピーオンライトのファイルシステムでは
+// 元のデータが物理的に消える保証はないため、ベストエフォートの処理となる
+func (p Path) SecureDelete(passes int) error {
+	if passes < 1 {
+		return fmt.Errorf("invalid passes: %d", passes)
+	}
+	// ファイルでない場合はエラー
+	if !p.IsFile() {
+		return os.ErrNotExist
+	}
+	f, err := os.OpenFile(string(p), os.O_WRONLY, 0)
+	if err != nil {
+		return err
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	for i := 0; i < passes && fi.Size() > 0; i++ {
+		if _, err := f.Seek(0, io.SeekStart); err != nil {
+			f.Close()
+			return err
+		}
+		if _, err := io.CopyN(f, rand.Reader, fi.Size()); err != nil {
+			f.Close()
+			return err
+		}
+		if err := f.Sync(); err != nil {
+			f.Close()
+			return err
+		}
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	return os.Remove(string(p))
+}
+
+// ファイルの先頭から最初の delim までを読み込む、delim も含む
+// delim が見つからずにファイルの末尾に達した場合は、読み込んだ内容と io.ErrUnexpectedEOF を返す
+func (p Path) ReadUntil(delim byte) ([]byte, error) {
+	f, err := p.FileOpen()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	data, err := bufio.NewReader(f).ReadBytes(delim)
+	if err == io.EOF {
+		return data, io.ErrUnexpectedEOF
+	}
+	return data, err
+}
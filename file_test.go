@@ -0,0 +1,637 @@
+package path
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"regexp"
+	"slices"
+	"strings"
+	"testing"
+	"testing/iotest"
+	"time"
+)
+
+func TestReadAllLimit(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	writeTestFile(t, p, "12345")
+	for _, max := range []int64{5, 100, math.MaxInt64} {
+		if data, err := p.ReadAllLimit(max); err != nil || string(data) != "12345" {
+			t.Errorf("ReadAllLimit(%d) = %q, %v", max, data, err)
+		}
+	}
+	if _, err := p.ReadAllLimit(4); !errors.Is(err, ErrTooLarge) {
+		t.Errorf("ReadAllLimit(4) = %v", err)
+	}
+}
+
+func TestSplitByLines(t *testing.T) {
+	root := NewPath(t.TempDir())
+	src := Join(root, "log.txt")
+	var b strings.Builder
+	for i := range 10 {
+		fmt.Fprintf(&b, "line %d\n", i+1)
+	}
+	writeTestFile(t, src, b.String())
+
+	got, err := src.SplitByLines(4, Join(root, "out"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !slices.Equal(got.ToBase(), Entries{"log_001.txt", "log_002.txt", "log_003.txt"}) {
+		t.Fatalf("SplitByLines = %v", got)
+	}
+	var joined strings.Builder
+	for i, want := range []int{4, 4, 2} {
+		s := readTestFile(t, got[i])
+		if n := strings.Count(s, "\n"); n != want {
+			t.Errorf("%s has %d lines, want %d", got[i], n, want)
+		}
+		joined.WriteString(s)
+	}
+	if joined.String() != b.String() {
+		t.Errorf("joined chunks = %q", joined.String())
+	}
+	if _, err := src.SplitByLines(0, Join(root, "out")); err == nil {
+		t.Error("SplitByLines(0) should fail")
+	}
+}
+
+func TestConcatenate(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a": "A", "b": "B", "c": "C"})
+	a, b, c := Join(root, "a"), Join(root, "b"), Join(root, "c")
+
+	dst := Join(root, "out", "abc")
+	if err := (Entries{a, b, c}).Concatenate(dst); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, dst); s != "ABC" {
+		t.Errorf("Concatenate = %q", s)
+	}
+	if err := (Entries{a, b}).ConcatenateWithSeparator(dst, []byte("\n")); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, dst); s != "A\nB" {
+		t.Errorf("ConcatenateWithSeparator = %q", s)
+	}
+	if err := (Entries{a, root}).Concatenate(dst); !errors.Is(err, os.ErrInvalid) {
+		t.Errorf("Concatenate(dir) = %v", err)
+	}
+	if s := readTestFile(t, dst); s != "A\nB" {
+		t.Errorf("failed Concatenate modified dst: %q", s)
+	}
+
+	// dst が連結するファイルに含まれていても内容を失わない
+	if err := (Entries{a, b}).Concatenate(a); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, a); s != "AB" {
+		t.Errorf("Concatenate into a source = %q", s)
+	}
+}
+
+func TestWriteNew(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "sub", "a.txt")
+	if err := p.WriteNew([]byte("first")); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.WriteNew([]byte("second")); !errors.Is(err, os.ErrExist) {
+		t.Errorf("WriteNew(existing) = %v", err)
+	}
+	if s := readTestFile(t, p); s != "first" {
+		t.Errorf("content = %q", s)
+	}
+}
+
+func TestEditAtomic(t *testing.T) {
+	root := NewPath(t.TempDir())
+	p := Join(root, "a.txt")
+	writeTestFile(t, p, "old")
+
+	errBoom := errors.New("boom")
+	err := p.EditAtomic(func(tmp Path) error {
+		os.WriteFile(tmp.String(), []byte("broken"), 0644)
+		return errBoom
+	})
+	if !errors.Is(err, errBoom) {
+		t.Errorf("EditAtomic error = %v", err)
+	}
+	if s := readTestFile(t, p); s != "old" {
+		t.Errorf("failed edit modified the file: %q", s)
+	}
+
+	err = p.EditAtomic(func(tmp Path) error {
+		return os.WriteFile(tmp.String(), []byte(readTestFile(t, tmp)+" new"), 0644)
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, p); s != "old new" {
+		t.Errorf("content = %q", s)
+	}
+	if des, _ := os.ReadDir(root.String()); len(des) != 1 {
+		t.Errorf("temporary files left: %v", des)
+	}
+}
+
+func TestPrepend(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	if err := p.Prepend([]byte("body")); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.Prepend([]byte("head\n")); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, p); s != "head\nbody" {
+		t.Errorf("Prepend = %q", s)
+	}
+	if err := p.Dir().Prepend([]byte("x")); err == nil {
+		t.Error("Prepend(dir) should fail")
+	}
+}
+
+func TestInsertAtLine(t *testing.T) {
+	tests := []struct {
+		content string
+		line    int
+		want    string
+	}{
+		{"a\nb\nc\n", 2, "a\nx\nb\nc\n"},
+		{"a\nb\n", 1, "x\na\nb\n"},
+		{"a\nb\n", 10, "a\nb\nx\n"},
+		{"a\nb", 10, "a\nb\nx\n"},
+		{"", 1, "x\n"},
+	}
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	for _, tt := range tests {
+		writeTestFile(t, p, tt.content)
+		if err := p.InsertAtLine(tt.line, "x"); err != nil {
+			t.Fatal(err)
+		}
+		if s := readTestFile(t, p); s != tt.want {
+			t.Errorf("InsertAtLine(%q, %d) = %q, want %q", tt.content, tt.line, s, tt.want)
+		}
+	}
+	if err := p.InsertAtLine(0, "x"); err == nil {
+		t.Error("InsertAtLine(0) should fail")
+	}
+}
+
+func TestReadLinesRange(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	writeTestFile(t, p, "1\n2\n3\r\n4\n5\n6\n7\n8\n9\n10")
+	tests := []struct {
+		start, count int
+		want         []string
+	}{
+		{3, 3, []string{"3", "4", "5"}},
+		{9, 5, []string{"9", "10"}},
+		{11, 1, []string{}},
+		{0, 1, []string{}},
+		{1, 0, []string{}},
+	}
+	for _, tt := range tests {
+		got, err := p.ReadLinesRange(tt.start, tt.count)
+		if err != nil || !slices.Equal(got, tt.want) {
+			t.Errorf("ReadLinesRange(%d, %d) = %q, %v", tt.start, tt.count, got, err)
+		}
+	}
+}
+
+func TestCreateSized(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "sub", "a.bin")
+	if err := p.CreateSized(1 << 20); err != nil {
+		t.Fatal(err)
+	}
+	if fi, err := os.Stat(p.String()); err != nil || fi.Size() != 1<<20 {
+		t.Errorf("size = %v, %v", fi.Size(), err)
+	}
+	if err := p.CreateSized(1); !errors.Is(err, os.ErrExist) {
+		t.Errorf("CreateSized(existing) = %v", err)
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	root := NewPath(t.TempDir())
+	p := Join(root, "sub", "a.bin")
+	if err := p.Allocate(8192); err != nil {
+		t.Fatal(err)
+	}
+	if fi, _ := os.Stat(p.String()); fi.Size() != 8192 {
+		t.Errorf("size = %d", fi.Size())
+	}
+
+	// 既存の内容は変更しない
+	q := Join(root, "b.bin")
+	writeTestFile(t, q, "data")
+	if err := q.Allocate(2); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, q); s != "data" {
+		t.Errorf("Allocate shrank the file: %q", s)
+	}
+
+	empty := Join(root, "empty.bin")
+	if err := empty.Allocate(0); err != nil {
+		t.Errorf("Allocate(0) = %v", err)
+	}
+	if fi, err := os.Stat(empty.String()); err != nil || fi.Size() != 0 {
+		t.Errorf("Allocate(0) created %v, %v", fi, err)
+	}
+}
+
+func TestWriteZeros(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.bin")
+	writeTestFile(t, p, "ab")
+	f, err := os.OpenFile(p.String(), os.O_WRONLY, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer f.Close()
+	if err := writeZeros(f, 10); err != nil {
+		t.Fatal(err)
+	}
+	// 既に size 以上の場合は何もしない
+	if err := writeZeros(f, 4); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, p); s != "ab"+strings.Repeat("\x00", 8) {
+		t.Errorf("content = %q", s)
+	}
+}
+
+// p を開いているファイルディスクリプタがあるか判定、/proc がない環境では ok = false
+func isFileOpen(p Path) (open, ok bool) {
+	fds, err := os.ReadDir("/proc/self/fd")
+	if err != nil {
+		return false, false
+	}
+	for _, fd := range fds {
+		if target, err := os.Readlink("/proc/self/fd/" + fd.Name()); err == nil && target == p.String() {
+			return true, true
+		}
+	}
+	return false, true
+}
+
+func TestCSVRows(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.csv")
+	writeTestFile(t, p, "a,b\n\"bad\"x,c\nd,e\nf,g\n")
+	rows, err := p.CSVRows()
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got [][]string
+	parseErrs := 0
+	for row, err := range rows {
+		if err != nil {
+			parseErrs++
+			continue
+		}
+		if open, ok := isFileOpen(p); ok && !open {
+			t.Error("file not open during iteration")
+		}
+		got = append(got, row)
+		if len(got) == 2 {
+			break
+		}
+	}
+	if parseErrs != 1 || len(got) != 2 || got[0][0] != "a" || got[1][0] != "d" {
+		t.Errorf("CSVRows = %v, %d parse errors", got, parseErrs)
+	}
+	if open, _ := isFileOpen(p); open {
+		t.Error("file left open after breaking out of the loop")
+	}
+	if _, err := Join(p.Dir(), "none.csv").CSVRows(); err == nil {
+		t.Error("CSVRows(missing) should fail")
+	}
+}
+
+func TestWriteReaderAtomic(t *testing.T) {
+	root := NewPath(t.TempDir())
+	p := Join(root, "a.txt")
+	writeTestFile(t, p, "old")
+
+	n, err := p.WriteReaderAtomic(strings.NewReader("new content"))
+	if err != nil || n != 11 {
+		t.Errorf("WriteReaderAtomic = %d, %v", n, err)
+	}
+
+	errBoom := errors.New("boom")
+	r := io.MultiReader(strings.NewReader("partial"), iotest.ErrReader(errBoom))
+	if _, err := p.WriteReaderAtomic(r); !errors.Is(err, errBoom) {
+		t.Errorf("WriteReaderAtomic error = %v", err)
+	}
+	if s := readTestFile(t, p); s != "new content" {
+		t.Errorf("failed write modified the file: %q", s)
+	}
+	if des, _ := os.ReadDir(root.String()); len(des) != 1 {
+		t.Errorf("temporary files left: %v", des)
+	}
+}
+
+func TestOpenAll(t *testing.T) {
+	root := NewPath(t.TempDir())
+	a, b := Join(root, "a"), Join(root, "b")
+	writeTestFile(t, a, "A")
+	writeTestFile(t, b, "B")
+
+	files, closeAll, err := Entries{a, b}.OpenAll()
+	if err != nil {
+		t.Fatal(err)
+	}
+	data, _ := io.ReadAll(io.MultiReader(files[0], files[1]))
+	if string(data) != "AB" {
+		t.Errorf("read %q", data)
+	}
+	if err := closeAll(); err != nil {
+		t.Error(err)
+	}
+	if _, _, err := (Entries{a, Join(root, "none")}).OpenAll(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("OpenAll(missing) = %v", err)
+	}
+}
+
+func TestAppendLineUnique(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	writeTestFile(t, p, "a\r\nb")
+	for _, tt := range []struct {
+		line  string
+		added bool
+	}{{"a", false}, {"b", false}, {"c", true}, {"c", false}, {"a b", true}} {
+		added, err := p.AppendLineUnique(tt.line)
+		if err != nil || added != tt.added {
+			t.Errorf("AppendLineUnique(%q) = %v, %v", tt.line, added, err)
+		}
+	}
+	if s := readTestFile(t, p); s != "a\r\nb\nc\na b\n" {
+		t.Errorf("content = %q", s)
+	}
+}
+
+func TestSedReplace(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	writeTestFile(t, p, "foo=1\r\nbar=2\nfoo=3")
+	n, err := p.SedReplace(regexp.MustCompile(`^foo=(\d)$`), "baz=$1")
+	if err != nil || n != 2 {
+		t.Errorf("SedReplace = %d, %v", n, err)
+	}
+	if s := readTestFile(t, p); s != "baz=1\r\nbar=2\nbaz=3" {
+		t.Errorf("content = %q", s)
+	}
+}
+
+func TestSedReplaceNoChange(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	writeTestFile(t, p, "foo=1\n")
+	mt := time.Now().Add(-time.Hour).Truncate(time.Second)
+	os.Chtimes(p.String(), mt, mt)
+	before, _ := os.Stat(p.String())
+
+	// 変更がない場合はファイルを置き換えない
+	n, err := p.SedReplace(regexp.MustCompile(`^bar=`), "baz=")
+	if err != nil || n != 0 {
+		t.Errorf("SedReplace = %d, %v", n, err)
+	}
+	after, _ := os.Stat(p.String())
+	if !os.SameFile(before, after) || !after.ModTime().Equal(mt) {
+		t.Error("file was rewritten without changes")
+	}
+	if des, _ := os.ReadDir(p.Dir().String()); len(des) != 1 {
+		t.Errorf("temporary files left: %v", des)
+	}
+}
+
+func TestReadTextNoBOM(t *testing.T) {
+	if got := StripBOM([]byte("\xEF\xBB\xBFabc")); string(got) != "abc" {
+		t.Errorf("StripBOM = %q", got)
+	}
+	if got := StripBOM([]byte("abc")); string(got) != "abc" {
+		t.Errorf("StripBOM(no BOM) = %q", got)
+	}
+	if got := StripBOM([]byte("\xEF\xBBabc")); string(got) != "\xEF\xBBabc" {
+		t.Errorf("StripBOM(partial BOM) = %q", got)
+	}
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	writeTestFile(t, p, "\xEF\xBB\xBFhello")
+	if s, err := p.ReadTextNoBOM(); err != nil || s != "hello" {
+		t.Errorf("ReadTextNoBOM = %q, %v", s, err)
+	}
+}
+
+func TestWriteTextIfChanged(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	if changed, err := p.WriteTextIfChanged("v1"); err != nil || !changed {
+		t.Errorf("WriteTextIfChanged(new) = %v, %v", changed, err)
+	}
+	old := time.Now().Add(-time.Hour).Truncate(time.Second)
+	os.Chtimes(p.String(), old, old)
+	if changed, err := p.WriteTextIfChanged("v1"); err != nil || changed {
+		t.Errorf("WriteTextIfChanged(same) = %v, %v", changed, err)
+	}
+	if fi, _ := os.Stat(p.String()); !fi.ModTime().Equal(old) {
+		t.Errorf("mtime changed to %v", fi.ModTime())
+	}
+	if changed, err := p.WriteTextIfChanged("v2"); err != nil || !changed {
+		t.Errorf("WriteTextIfChanged(different) = %v, %v", changed, err)
+	}
+	if s := readTestFile(t, p); s != "v2" {
+		t.Errorf("content = %q", s)
+	}
+}
+
+func TestAppendWriter(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.log")
+	writeTestFile(t, p, "1\n")
+	w, err := p.AppendWriter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "2\n")
+	if err := w.Close(); err != nil {
+		t.Fatal(err)
+	}
+	// ロックは解除されている
+	w, err = p.AppendWriter()
+	if err != nil {
+		t.Fatal(err)
+	}
+	io.WriteString(w, "3\n")
+	w.Close()
+	if s := readTestFile(t, p); s != "1\n2\n3\n" {
+		t.Errorf("content = %q", s)
+	}
+}
+
+func TestChunks(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.bin")
+	writeTestFile(t, p, "abcdefghij")
+	chunks, err := p.Chunks(4)
+	if err != nil {
+		t.Fatal(err)
+	}
+	var got []string
+	for b, err := range chunks {
+		if err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, string(b))
+	}
+	if !slices.Equal(got, []string{"abcd", "efgh", "ij"}) {
+		t.Errorf("Chunks = %q", got)
+	}
+	if _, err := p.Chunks(0); err == nil {
+		t.Error("Chunks(0) should fail")
+	}
+}
+
+func TestReadSplit(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	writeTestFile(t, p, "a;b;;c;")
+	got, err := p.ReadSplit(';')
+	if err != nil || !slices.Equal(got, []string{"a", "b", "", "c"}) {
+		t.Errorf("ReadSplit = %q, %v", got, err)
+	}
+}
+
+func TestWriteReadList(t *testing.T) {
+	root := NewPath(t.TempDir())
+	entries := Entries{"/a/with space.txt", "/b/c"}
+	p := Join(root, "list.txt")
+	if err := entries.WriteList(p); err != nil {
+		t.Fatal(err)
+	}
+	got, err := ReadList(p)
+	if err != nil || !slices.Equal(got, entries) {
+		t.Errorf("ReadList = %v, %v", got, err)
+	}
+
+	withNewline := Entries{"/a/line\nbreak", "/b"}
+	q := Join(root, "list.bin")
+	if err := withNewline.WriteListNull(q); err != nil {
+		t.Fatal(err)
+	}
+	got, err = ReadListNull(q)
+	if err != nil || !slices.Equal(got, withNewline) {
+		t.Errorf("ReadListNull = %q, %v", got, err)
+	}
+}
+
+func TestReadString(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	writeTestFile(t, p, "hello world")
+	s, err := p.ReadString()
+	if err != nil || s != "hello world" {
+		t.Errorf("ReadString = %q, %v", s, err)
+	}
+	b, err := p.ReadBytes()
+	if err != nil || string(b) != "hello world" {
+		t.Errorf("ReadBytes = %q, %v", b, err)
+	}
+	if _, err := Join(p.Dir(), "none").ReadString(); err == nil {
+		t.Error("ReadString(missing) should fail")
+	}
+}
+
+func TestWriteString(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	if err := p.WriteString("hello", 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := p.AppendString(" world"); err != nil {
+		t.Fatal(err)
+	}
+	s, err := p.ReadString()
+	if err != nil || s != "hello world" {
+		t.Errorf("ReadString = %q, %v", s, err)
+	}
+	b, err := p.ReadBytes()
+	if err != nil || string(b) != "hello world" {
+		t.Errorf("ReadBytes = %q, %v", b, err)
+	}
+}
+
+func TestLineEndings(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	tests := []struct {
+		content, want string
+	}{
+		{"a\nb\n", "\n"},
+		{"a\r\nb\r\n", "\r\n"},
+		{"a\r\nb\n", "mixed"},
+		{"no newline", "\n"},
+	}
+	for _, tt := range tests {
+		writeTestFile(t, p, tt.content)
+		if got, err := p.DetectLineEnding(); err != nil || got != tt.want {
+			t.Errorf("DetectLineEnding(%q) = %q, %v", tt.content, got, err)
+		}
+	}
+
+	writeTestFile(t, p, "a\r\nb\nc")
+	if err := p.NormalizeLineEndings("\r\n"); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, p); s != "a\r\nb\r\nc" {
+		t.Errorf("NormalizeLineEndings(CRLF) = %q", s)
+	}
+	if err := p.NormalizeLineEndings("\n"); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, p); s != "a\nb\nc" {
+		t.Errorf("NormalizeLineEndings(LF) = %q", s)
+	}
+	if err := p.NormalizeLineEndings("\r"); err == nil {
+		t.Error("NormalizeLineEndings(CR) should fail")
+	}
+}
+
+func TestReadWriteLines(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	lines := []string{"one", "", "three"}
+	if err := p.WriteLines(lines, 0644); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, p); s != "one\n\nthree\n" {
+		t.Errorf("content = %q", s)
+	}
+	got, err := p.ReadLines()
+	if err != nil || !slices.Equal(got, lines) {
+		t.Errorf("ReadLines = %q, %v", got, err)
+	}
+}
+
+func TestSecureDelete(t *testing.T) {
+	root := NewPath(t.TempDir())
+	for _, content := range []string{"secret", ""} {
+		p := Join(root, "a.txt")
+		writeTestFile(t, p, content)
+		if err := p.SecureDelete(2); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := os.Lstat(p.String()); err == nil {
+			t.Errorf("SecureDelete(%q) left the file", content)
+		}
+	}
+	if err := Join(root, "none").SecureDelete(1); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("SecureDelete(missing) = %v", err)
+	}
+	if err := Join(root, "none").SecureDelete(0); err == nil {
+		t.Error("SecureDelete(0) should fail")
+	}
+}
+
+func TestReadUntil(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	writeTestFile(t, p, "a;b;;c;")
+	data, err := p.ReadUntil(';')
+	if err != nil || string(data) != "a;" {
+		t.Errorf("ReadUntil = %q, %v", data, err)
+	}
+	data, err = p.ReadUntil('#')
+	if !errors.Is(err, io.ErrUnexpectedEOF) || string(data) != "a;b;;c;" {
+		t.Errorf("ReadUntil(missing) = %q, %v", data, err)
+	}
+}
@@ -1,3 +1,11 @@
 module github.com/kawasaki8901/path
 
 go 1.24.0
+
+require (
+	github.com/dsnet/compress v0.0.1
+	github.com/klauspost/compress v1.17.11
+	golang.org/x/sys v0.28.0
+	golang.org/x/text v0.21.0
+	gopkg.in/yaml.v3 v3.0.1
+)
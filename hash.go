@@ -0,0 +1,166 @@
+package path
+
+// ファイルのハッシュ値を扱う処理
+
+import (
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/gob"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ファイルの SHA256 を計算
+func (p Path) sha256Sum() ([]byte, error) {
+	f, err := p.FileOpen()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, f); err != nil {
+		return nil, err
+	}
+	return h.Sum(nil), nil
+}
+
+// ファイルの SHA256 が 16 進数文字列 expected と一致するか判定
+// 大文字小文字は区別せず、定数時間で比較する。expected の長さが不正な場合はエラー
+func (p Path) HashEquals(expected string) (bool, error) {
+	want, err := hex.DecodeString(expected)
+	if err != nil {
+		return false, fmt.Errorf("invalid digest %q: %w", expected, err)
+	}
+	if len(want) != sha256.Size {
+		return false, fmt.Errorf("invalid digest length: %d", len(expected))
+	}
+	sum, err := p.sha256Sum()
+	if err != nil {
+		return false, err
+	}
+	return subtle.ConstantTimeCompare(sum, want) == 1, nil
+}
+
+// ディレクトリ配下の構造と内容全体を表す SHA256 を 16 進数文字列で取得
+// 名前順に辿り、各要素の相対パス、パーミッション、内容のハッシュ値をまとめてハッシュ化する
+// シンボリックリンクはリンク先を辿らず、リンク先のパスをハッシュ化する
+func (p Path) TreeHash() (string, error) {
+	if !p.IsDir() {
+		return "", os.ErrNotExist
+	}
+	h := sha256.New()
+	// filepath.WalkDir は名前順に辿るため、結果は常に同じになる
+	err := filepath.WalkDir(string(p), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(string(p), path)
+		if err != nil {
+			return err
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		var sum []byte
+		switch {
+		case fi.IsDir():
+		case fi.Mode()&os.ModeSymlink != 0:
+			target, err := os.Readlink(path)
+			if err != nil {
+				return err
+			}
+			s := sha256.Sum256([]byte(target))
+			sum = s[:]
+		default:
+			sum, err = NewPath(path).sha256Sum()
+			if err != nil {
+				return err
+			}
+		}
+		fmt.Fprintf(h, "%s\x00%s\x00%x\n", filepath.ToSlash(rel), fi.Mode(), sum)
+		return nil
+	})
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// HashCache のキャッシュの 1 要素
+type hashCacheEntry struct {
+	ModTime time.Time
+	Size    int64
+	Sum     string
+}
+
+// ファイルの SHA256 を更新日時とサイズをキーにキャッシュする
+// gob でエンコードして保存し、プロセスをまたいで再利用できる
+// 並行して使う場合は呼び出し側で排他制御すること
+type HashCache struct {
+	entries map[Path]hashCacheEntry
+
+	// ファイルを開く処理、nil の場合は Path.FileOpen を使う (テスト用)
+	open func(Path) (io.ReadCloser, error)
+}
+
+// ファイルの SHA256 を 16 進数文字列で取得
+// 前回から更新日時とサイズが変わっていない場合は、ファイルを読まずにキャッシュした値を返す
+func (c *HashCache) Hash(p Path) (string, error) {
+	fi, err := os.Stat(string(p))
+	if err != nil {
+		return "", err
+	}
+	if e, ok := c.entries[p]; ok && e.ModTime.Equal(fi.ModTime()) && e.Size == fi.Size() {
+		return e.Sum, nil
+	}
+
+	open := c.open
+	if open == nil {
+		open = func(p Path) (io.ReadCloser, error) {
+			return p.FileOpen()
+		}
+	}
+	r, err := open(p)
+	if err != nil {
+		return "", err
+	}
+	defer r.Close()
+	h := sha256.New()
+	if _, err := io.Copy(h, r); err != nil {
+		return "", err
+	}
+	sum := hex.EncodeToString(h.Sum(nil))
+
+	if c.entries == nil {
+		c.entries = map[Path]hashCacheEntry{}
+	}
+	c.entries[p] = hashCacheEntry{ModTime: fi.ModTime(), Size: fi.Size(), Sum: sum}
+	return sum, nil
+}
+
+// キャッシュを gob でエンコードしてファイルに保存
+func (c *HashCache) Save(p Path) error {
+	return p.writeAtomic(func(w io.Writer) error {
+		return gob.NewEncoder(w).Encode(c.entries)
+	})
+}
+
+// gob で保存したキャッシュをファイルから読み込む
+func LoadHashCache(p Path) (*HashCache, error) {
+	f, err := p.FileOpen()
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+	c := &HashCache{}
+	if err := gob.NewDecoder(f).Decode(&c.entries); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
@@ -0,0 +1,112 @@
+package path
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+	"os"
+	"strings"
+	"testing"
+)
+
+func TestHashEquals(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	writeTestFile(t, p, "hello")
+	sum := sha256.Sum256([]byte("hello"))
+	digest := hex.EncodeToString(sum[:])
+
+	ok, err := p.HashEquals(digest)
+	if err != nil || !ok {
+		t.Errorf("HashEquals(match) = %v, %v", ok, err)
+	}
+	ok, err = p.HashEquals(strings.ToUpper(digest))
+	if err != nil || !ok {
+		t.Errorf("HashEquals(upper) = %v, %v", ok, err)
+	}
+	other := sha256.Sum256([]byte("world"))
+	ok, err = p.HashEquals(hex.EncodeToString(other[:]))
+	if err != nil || ok {
+		t.Errorf("HashEquals(mismatch) = %v, %v", ok, err)
+	}
+	if _, err := p.HashEquals(digest[:10]); err == nil {
+		t.Error("HashEquals(short) should fail")
+	}
+}
+
+func TestTreeHash(t *testing.T) {
+	build := func() Path {
+		return buildTestTree(t, map[string]string{"a.txt": "a", "sub/b.txt": "b"})
+	}
+	r1, r2 := build(), build()
+	h1, err := r1.TreeHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	h2, err := r2.TreeHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h1 != h2 {
+		t.Errorf("identical trees differ: %s != %s", h1, h2)
+	}
+
+	writeTestFile(t, Join(r2, "sub", "b.txt"), "changed")
+	h3, err := r2.TreeHash()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if h3 == h1 {
+		t.Error("digest did not change after modifying a file")
+	}
+}
+
+func TestHashCache(t *testing.T) {
+	dir := NewPath(t.TempDir())
+	p := Join(dir, "a.txt")
+	writeTestFile(t, p, "hello")
+
+	opens := 0
+	c := &HashCache{open: func(p Path) (io.ReadCloser, error) {
+		opens++
+		return p.FileOpen()
+	}}
+	s1, err := c.Hash(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	s2, err := c.Hash(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s1 != s2 || opens != 1 {
+		t.Errorf("second Hash re-read the file: opens=%d", opens)
+	}
+
+	// サイズが変われば再計算
+	writeTestFile(t, p, "hello, world")
+	s3, err := c.Hash(p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s3 == s1 || opens != 2 {
+		t.Errorf("modified file not re-hashed: opens=%d", opens)
+	}
+
+	// 保存したキャッシュを読み込んで再利用
+	cacheFile := Join(dir, "cache.gob")
+	if err := c.Save(cacheFile); err != nil {
+		t.Fatal(err)
+	}
+	loaded, err := LoadHashCache(cacheFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	loaded.open = func(Path) (io.ReadCloser, error) {
+		t.Error("loaded cache re-read the file")
+		return nil, os.ErrInvalid
+	}
+	s4, err := loaded.Hash(p)
+	if err != nil || s4 != s3 {
+		t.Errorf("loaded Hash = %s, %v; want %s", s4, err, s3)
+	}
+}
@@ -0,0 +1,53 @@
+package path
+
+// JSON との相互変換
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// Path を JSON の文字列に変換
+func (p Path) MarshalJSON() ([]byte, error) {
+	return json.Marshal(string(p))
+}
+
+// JSON の文字列から Path に変換、文字列以外の場合はエラー
+func (p *Path) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) == 0 || data[0] != '"' {
+		return fmt.Errorf("path: cannot unmarshal %s into Path, must be a JSON string", data)
+	}
+	var s string
+	if err := json.Unmarshal(data, &s); err != nil {
+		return err
+	}
+	*p = NewPath(s)
+	return nil
+}
+
+// Entries を JSON の文字列の配列に変換
+func (e Entries) MarshalJSON() ([]byte, error) {
+	return json.Marshal(e.ToString())
+}
+
+// JSON の文字列の配列から Entries に変換、配列以外や文字列以外の要素がある場合はエラー
+func (e *Entries) UnmarshalJSON(data []byte) error {
+	data = bytes.TrimSpace(data)
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) == 0 || data[0] != '[' {
+		return fmt.Errorf("path: cannot unmarshal %s into Entries, must be a JSON array", data)
+	}
+	var paths []Path
+	if err := json.Unmarshal(data, &paths); err != nil {
+		return err
+	}
+	*e = Entries(paths)
+	return nil
+}
@@ -0,0 +1,43 @@
+package path
+
+import (
+	"encoding/json"
+	"slices"
+	"testing"
+)
+
+func TestPathJSON(t *testing.T) {
+	type config struct {
+		Dir   Path    `json:"dir"`
+		Files Entries `json:"files"`
+	}
+	in := config{Dir: "/var/data", Files: Entries{"a.txt", "sub/b.txt"}}
+	data, err := json.Marshal(in)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := string(data); s != `{"dir":"/var/data","files":["a.txt","sub/b.txt"]}` {
+		t.Errorf("Marshal = %s", s)
+	}
+	var out config
+	if err := json.Unmarshal(data, &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Dir != in.Dir || !slices.Equal(out.Files, in.Files) {
+		t.Errorf("Unmarshal = %+v", out)
+	}
+
+	out = config{Dir: "keep", Files: Entries{"keep"}}
+	if err := json.Unmarshal([]byte(`{"dir":null,"files":null}`), &out); err != nil {
+		t.Fatal(err)
+	}
+	if out.Dir != "keep" || len(out.Files) != 1 {
+		t.Errorf("Unmarshal(null) = %+v", out)
+	}
+
+	for _, bad := range []string{`{"dir":1}`, `{"files":"a"}`, `{"files":[1]}`} {
+		if err := json.Unmarshal([]byte(bad), &out); err == nil {
+			t.Errorf("Unmarshal(%s) should fail", bad)
+		}
+	}
+}
@@ -0,0 +1,63 @@
+package path
+
+// シンボリックリンクを扱う処理
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// link の位置に、p を指す相対パスのシンボリックリンクを作成
+// ツリーごと移動してもリンクが切れない。link の親ディレクトリは作成する
+func (p Path) RelSymlink(link Path) error {
+	target, err := p.Abs()
+	if err != nil {
+		return err
+	}
+	absLink, err := link.Abs()
+	if err != nil {
+		return err
+	}
+	rel, err := filepath.Rel(string(absLink.DirName()), string(target))
+	if err != nil {
+		return err
+	}
+	if err := link.DirName().CreDir(); err != nil {
+		return err
+	}
+	return os.Symlink(rel, string(link))
+}
+
+// Path がシンボリックリンクか判定、リンク先は辿らない。存在しない場合は false
+func (p Path) IsSymlink() bool {
+	fi, err := os.Lstat(string(p))
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeSymlink != 0
+}
+
+// p の位置に target を指すシンボリックリンクを作成
+func (p Path) Symlink(target Path) error {
+	return os.Symlink(string(target), string(p))
+}
+
+// シンボリックリンクのリンク先を取得
+func (p Path) ReadLink() (Path, error) {
+	target, err := os.Readlink(string(p))
+	if err != nil {
+		return "", err
+	}
+	return NewPath(target), nil
+}
+
+// シンボリックリンクをすべて辿った実際のパスを取得
+// 途中の要素が存在しない場合はエラー
+func (p Path) Resolve() (Path, error) {
+	real, err := filepath.EvalSymlinks(string(p))
+	if err != nil {
+		return "", fmt.Errorf("resolve %s: %w", p, err)
+	}
+	return NewPath(real), nil
+}
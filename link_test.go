@@ -0,0 +1,60 @@
+package path
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestRelSymlink(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"data/a.txt": "hello"})
+	link := Join(root, "links", "deep", "a")
+	if err := Join(root, "data", "a.txt").RelSymlink(link); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	if target, _ := os.Readlink(link.String()); NewPath(target) != fromSlash("../../data/a.txt") {
+		t.Errorf("link target = %q", target)
+	}
+
+	// ツリーごと移動してもリンクは切れない
+	moved := Join(NewPath(t.TempDir()), "moved")
+	if err := os.Rename(root.String(), moved.String()); err != nil {
+		t.Fatal(err)
+	}
+	if s := readTestFile(t, Join(moved, "links", "deep", "a")); s != "hello" {
+		t.Errorf("content through link = %q", s)
+	}
+}
+
+func TestSymlink(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "hello"})
+	link := Join(root, "link")
+	if err := link.Symlink("a.txt"); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	if !link.IsSymlink() || Join(root, "a.txt").IsSymlink() || Join(root, "none").IsSymlink() {
+		t.Error("IsSymlink returned wrong result")
+	}
+	if target, err := link.ReadLink(); err != nil || target != "a.txt" {
+		t.Errorf("ReadLink = %q, %v", target, err)
+	}
+	if _, err := Join(root, "a.txt").ReadLink(); err == nil {
+		t.Error("ReadLink(file) should fail")
+	}
+}
+
+func TestResolve(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"real/a.txt": ""})
+	root, _ = root.Resolve()
+	if err := Join(root, "l1").Symlink("real"); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	Join(root, "l2").Symlink("l1")
+	got, err := Join(root, "l2", "a.txt").Resolve()
+	if err != nil || got != Join(root, "real", "a.txt") {
+		t.Errorf("Resolve = %q, %v", got, err)
+	}
+	if _, err := Join(root, "none", "a.txt").Resolve(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Resolve(missing) = %v", err)
+	}
+}
@@ -0,0 +1,84 @@
+package path
+
+// ディレクトリ内の一覧を絞り込み、並べ替え、ページ分割して取得する処理
+
+import (
+	"os"
+	"sort"
+)
+
+// 一覧の並べ替えの基準
+type SortKey int
+
+const (
+	SortKeyName    SortKey = iota // 名前順
+	SortKeySize                   // サイズ順
+	SortKeyModTime                // 更新日時順
+)
+
+// ListPage のオプション
+type ListOptions struct {
+	ExtFilter  []Ext   // 抽出する拡張子、空の場合はすべて
+	SortKey    SortKey // 並べ替えの基準
+	Descending bool    // 降順にする場合は true
+	Offset     int     // 先頭から読み飛ばす数
+	Limit      int     // 取得する最大数、0 以下の場合は制限なし
+}
+
+// ディレクトリ内のファイル、ディレクトリを opts に従って取得
+// ページ分割前の総数も返す
+func (p Path) ListPage(opts ListOptions) (Entries, int, error) {
+	entries, err := p.Entries()
+	if err != nil {
+		return Entries{}, 0, err
+	}
+	if len(opts.ExtFilter) > 0 {
+		entries = entries.ExtractExt(opts.ExtFilter...)
+	}
+
+	// 各要素の情報は一度だけ取得する
+	infos := make([]os.FileInfo, len(entries))
+	for i, entry := range entries {
+		fi, err := os.Stat(string(entry))
+		if err != nil {
+			return Entries{}, 0, err
+		}
+		infos[i] = fi
+	}
+	idx := make([]int, len(entries))
+	for i := range idx {
+		idx[i] = i
+	}
+	less := func(a, b int) bool {
+		switch opts.SortKey {
+		case SortKeySize:
+			if infos[a].Size() != infos[b].Size() {
+				return infos[a].Size() < infos[b].Size()
+			}
+		case SortKeyModTime:
+			if !infos[a].ModTime().Equal(infos[b].ModTime()) {
+				return infos[a].ModTime().Before(infos[b].ModTime())
+			}
+		}
+		return entries[a] < entries[b]
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		if opts.Descending {
+			return less(idx[j], idx[i])
+		}
+		return less(idx[i], idx[j])
+	})
+
+	// ページ分割
+	total := len(idx)
+	start := min(max(opts.Offset, 0), total)
+	end := total
+	if opts.Limit > 0 {
+		end = min(start+opts.Limit, total)
+	}
+	page := make(Entries, 0, end-start)
+	for _, i := range idx[start:end] {
+		page = append(page, entries[i])
+	}
+	return page, total, nil
+}
@@ -0,0 +1,38 @@
+package path
+
+import (
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestListPage(t *testing.T) {
+	root := buildTestTree(t, map[string]string{
+		"a.txt": strings.Repeat("x", 3),
+		"b.txt": strings.Repeat("x", 5),
+		"c.log": strings.Repeat("x", 9),
+		"d.txt": strings.Repeat("x", 1),
+		"e.txt": strings.Repeat("x", 4),
+	})
+
+	opts := ListOptions{ExtFilter: []Ext{".txt"}, SortKey: SortKeySize, Descending: true, Limit: 2}
+	page, total, err := root.ListPage(opts)
+	if err != nil || total != 4 || !slices.Equal(page.ToBase(), Entries{"b.txt", "e.txt"}) {
+		t.Errorf("ListPage(page 1) = %v, %d, %v", page, total, err)
+	}
+	opts.Offset = 2
+	page, total, err = root.ListPage(opts)
+	if err != nil || total != 4 || !slices.Equal(page.ToBase(), Entries{"a.txt", "d.txt"}) {
+		t.Errorf("ListPage(page 2) = %v, %d, %v", page, total, err)
+	}
+	opts.Offset = 10
+	page, total, err = root.ListPage(opts)
+	if err != nil || total != 4 || len(page) != 0 {
+		t.Errorf("ListPage(past end) = %v, %d, %v", page, total, err)
+	}
+
+	page, total, err = root.ListPage(ListOptions{})
+	if err != nil || total != 5 || !slices.Equal(page.ToBase(), Entries{"a.txt", "b.txt", "c.log", "d.txt", "e.txt"}) {
+		t.Errorf("ListPage(default) = %v, %d, %v", page, total, err)
+	}
+}
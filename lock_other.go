@@ -0,0 +1,20 @@
+//go:build !(linux || darwin || freebsd || netbsd || openbsd || dragonfly || windows)
+
+package path
+
+// ファイルのロック (未対応のプラットフォーム)
+
+import (
+	"errors"
+	"os"
+)
+
+// ファイルの排他ロックを取得、未対応のためエラーを返す
+func lockFile(f *os.File) error {
+	return errors.ErrUnsupported
+}
+
+// ファイルのロックを解除、未対応のためエラーを返す
+func unlockFile(f *os.File) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,20 @@
+//go:build linux || darwin || freebsd || netbsd || openbsd || dragonfly
+
+package path
+
+// ファイルのロック (Unix)
+
+import (
+	"os"
+	"syscall"
+)
+
+// ファイルの排他ロックを取得、取得できるまで待つ
+func lockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_EX)
+}
+
+// ファイルのロックを解除
+func unlockFile(f *os.File) error {
+	return syscall.Flock(int(f.Fd()), syscall.LOCK_UN)
+}
@@ -0,0 +1,15 @@
+//go:build !windows
+
+package path
+
+// 長いパスの扱い (Windows 以外)
+
+// パスが MAX_PATH の制限を超えるか判定、Windows 以外では常に false
+func (p Path) ExceedsMaxPath() bool {
+	return false
+}
+
+// 長いパスを扱えるよう \\?\ を付与したパスを取得、Windows 以外では p をそのまま返す
+func (p Path) LongPathPrefixed() Path {
+	return p
+}
@@ -0,0 +1,18 @@
+//go:build !windows
+
+package path
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLongPath(t *testing.T) {
+	p := NewPath("/" + strings.Repeat("a", 300))
+	if p.ExceedsMaxPath() {
+		t.Error("ExceedsMaxPath should be false")
+	}
+	if got := p.LongPathPrefixed(); got != p {
+		t.Errorf("LongPathPrefixed = %q", got)
+	}
+}
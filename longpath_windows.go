@@ -0,0 +1,40 @@
+//go:build windows
+
+package path
+
+// 長いパスの扱い (Windows)
+
+import (
+	"strings"
+)
+
+// Windows のパスの最大長 (終端の NUL を含む)
+const maxPath = 260
+
+// パスが MAX_PATH の制限を超えるか判定
+func (p Path) ExceedsMaxPath() bool {
+	abs, err := p.Abs()
+	if err != nil {
+		abs = p
+	}
+	return len(abs) >= maxPath
+}
+
+// MAX_PATH の制限を超える場合、長いパスを扱えるよう \\?\ を付与した絶対パスを取得
+// 制限を超えない場合や既に付与されている場合は p をそのまま返す
+func (p Path) LongPathPrefixed() Path {
+	s := string(p)
+	if strings.HasPrefix(s, `\\?\`) || !p.ExceedsMaxPath() {
+		return p
+	}
+	abs, err := p.Abs()
+	if err != nil {
+		return p
+	}
+	s = string(abs)
+	if strings.HasPrefix(s, `\\`) {
+		// UNC パス
+		return Path(`\\?\UNC\` + s[2:])
+	}
+	return Path(`\\?\` + s)
+}
@@ -0,0 +1,67 @@
+package path
+
+// 先頭のバイト列 (マジックナンバー) からファイルの種類を判定する処理
+
+import (
+	"bytes"
+	"io"
+	"sync"
+)
+
+// ファイルの種類を表すシグネチャ
+type signature struct {
+	name   string
+	magic  []byte
+	offset int
+}
+
+var (
+	signaturesMu sync.RWMutex
+	signatures   = []signature{
+		{"PNG", []byte("\x89PNG\r\n\x1a\n"), 0},
+		{"JPEG", []byte{0xFF, 0xD8, 0xFF}, 0},
+		{"GIF", []byte("GIF87a"), 0},
+		{"GIF", []byte("GIF89a"), 0},
+		{"PDF", []byte("%PDF-"), 0},
+		{"ZIP", []byte("PK\x03\x04"), 0},
+		{"GZIP", []byte{0x1F, 0x8B}, 0},
+	}
+)
+
+// ファイルの種類の判定に使うシグネチャを登録
+// offset はファイルの先頭から magic が現れる位置
+// 組み込みのシグネチャより先に判定し、後から登録したものほど優先する
+func RegisterSignature(name string, magic []byte, offset int) {
+	signaturesMu.Lock()
+	defer signaturesMu.Unlock()
+	signatures = append([]signature{{name, bytes.Clone(magic), offset}}, signatures...)
+}
+
+// ファイルの先頭のバイト列から種類を判定し、種類の名前を返す
+// 登録されたシグネチャに一致しない場合は "unknown" を返す
+func (p Path) DetectType() (string, error) {
+	signaturesMu.RLock()
+	defer signaturesMu.RUnlock()
+
+	size := 0
+	for _, sig := range signatures {
+		size = max(size, sig.offset+len(sig.magic))
+	}
+	f, err := p.FileOpen()
+	if err != nil {
+		return "", err
+	}
+	defer f.Close()
+	head, err := io.ReadAll(io.LimitReader(f, int64(size)))
+	if err != nil {
+		return "", err
+	}
+
+	for _, sig := range signatures {
+		end := sig.offset + len(sig.magic)
+		if sig.offset >= 0 && end <= len(head) && bytes.Equal(head[sig.offset:end], sig.magic) {
+			return sig.name, nil
+		}
+	}
+	return "unknown", nil
+}
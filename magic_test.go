@@ -0,0 +1,76 @@
+package path
+
+import (
+	"slices"
+	"testing"
+)
+
+func TestDetectType(t *testing.T) {
+	tests := []struct {
+		content, want string
+	}{
+		{"\x89PNG\r\n\x1a\n....", "PNG"},
+		{"%PDF-1.7\n", "PDF"},
+		{"GIF89a", "GIF"},
+		{"\xFF\xD8\xFF\xE0", "JPEG"},
+		{"PK\x03\x04", "ZIP"},
+		{"plain text", "unknown"},
+		{"", "unknown"},
+	}
+	p := Join(NewPath(t.TempDir()), "file")
+	for _, tt := range tests {
+		writeTestFile(t, p, tt.content)
+		if got, err := p.DetectType(); err != nil || got != tt.want {
+			t.Errorf("DetectType(%q) = %q, %v, want %q", tt.content, got, err, tt.want)
+		}
+	}
+	if _, err := Join(p.Dir(), "none").DetectType(); err == nil {
+		t.Error("DetectType(missing) should fail")
+	}
+}
+
+// テスト終了時に登録済みのシグネチャを元に戻す
+func restoreSignatures(t *testing.T) {
+	signaturesMu.Lock()
+	saved := slices.Clone(signatures)
+	signaturesMu.Unlock()
+	t.Cleanup(func() {
+		signaturesMu.Lock()
+		signatures = saved
+		signaturesMu.Unlock()
+	})
+}
+
+func TestRegisterSignature(t *testing.T) {
+	restoreSignatures(t)
+	magic := []byte("ftypisom")
+	RegisterSignature("MP4", magic, 4)
+	magic[0] = 'x' // 登録後に変更しても影響しない
+
+	p := Join(NewPath(t.TempDir()), "movie")
+	writeTestFile(t, p, "\x00\x00\x00\x18ftypisom")
+	if got, err := p.DetectType(); err != nil || got != "MP4" {
+		t.Errorf("DetectType = %q, %v", got, err)
+	}
+	writeTestFile(t, p, "ftypisom")
+	if got, _ := p.DetectType(); got != "unknown" {
+		t.Errorf("DetectType(wrong offset) = %q", got)
+	}
+}
+
+func TestRegisterSignatureOverride(t *testing.T) {
+	restoreSignatures(t)
+	p := Join(NewPath(t.TempDir()), "doc")
+	writeTestFile(t, p, "PK\x03\x04....")
+
+	// 組み込みのシグネチャより優先する
+	RegisterSignature("DOCX", []byte("PK\x03\x04"), 0)
+	if got, err := p.DetectType(); err != nil || got != "DOCX" {
+		t.Errorf("DetectType = %q, %v", got, err)
+	}
+	// 後から登録したものほど優先する
+	RegisterSignature("XLSX", []byte("PK\x03\x04"), 0)
+	if got, err := p.DetectType(); err != nil || got != "XLSX" {
+		t.Errorf("DetectType = %q, %v", got, err)
+	}
+}
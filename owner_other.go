@@ -0,0 +1,19 @@
+//go:build !unix
+
+package path
+
+// ファイルの所有者を扱う処理 (Unix 以外)
+
+import (
+	"errors"
+)
+
+// Entries から指定の uid が所有するもののみ抽出、Unix 以外では未対応
+func (e Entries) OwnedBy(uid int) (Entries, error) {
+	return nil, errors.ErrUnsupported
+}
+
+// src の所有者とグループを p に適用、Unix 以外では未対応
+func (p Path) CopyOwnerFrom(src Path) error {
+	return errors.ErrUnsupported
+}
@@ -0,0 +1,43 @@
+//go:build unix
+
+package path
+
+// ファイルの所有者を扱う処理 (Unix)
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// Entries から指定の uid が所有するもののみ抽出
+func (e Entries) OwnedBy(uid int) (Entries, error) {
+	entries := Entries{}
+	for _, entry := range e {
+		fi, err := os.Stat(string(entry))
+		if err != nil {
+			return nil, err
+		}
+		st, ok := fi.Sys().(*syscall.Stat_t)
+		if !ok {
+			return nil, fmt.Errorf("%s: no ownership information", entry)
+		}
+		if int(st.Uid) == uid {
+			entries = append(entries, entry)
+		}
+	}
+	return entries, nil
+}
+
+// src の所有者とグループを p に適用
+func (p Path) CopyOwnerFrom(src Path) error {
+	fi, err := os.Stat(string(src))
+	if err != nil {
+		return err
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fmt.Errorf("%s: no ownership information", src)
+	}
+	return os.Chown(string(p), int(st.Uid), int(st.Gid))
+}
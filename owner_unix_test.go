@@ -0,0 +1,45 @@
+//go:build unix
+
+package path
+
+import (
+	"os"
+	"slices"
+	"syscall"
+	"testing"
+)
+
+func TestOwnedBy(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a": "", "b": ""})
+	entries := Entries{Join(root, "a"), Join(root, "b")}
+	got, err := entries.OwnedBy(os.Geteuid())
+	if err != nil || !slices.Equal(got, entries) {
+		t.Errorf("OwnedBy(self) = %v, %v", got, err)
+	}
+	got, err = entries.OwnedBy(os.Geteuid() + 1)
+	if err != nil || len(got) != 0 {
+		t.Errorf("OwnedBy(other) = %v, %v", got, err)
+	}
+	if _, err := (Entries{Join(root, "none")}).OwnedBy(0); err == nil {
+		t.Error("OwnedBy(missing) should fail")
+	}
+}
+
+func TestCopyOwnerFrom(t *testing.T) {
+	if os.Geteuid() != 0 {
+		t.Skip("changing ownership requires root")
+	}
+	root := buildTestTree(t, map[string]string{"src": "", "dst": ""})
+	src, dst := Join(root, "src"), Join(root, "dst")
+	if err := os.Chown(src.String(), 1234, 5678); err != nil {
+		t.Fatal(err)
+	}
+	if err := dst.CopyOwnerFrom(src); err != nil {
+		t.Fatal(err)
+	}
+	fi, _ := os.Stat(dst.String())
+	st := fi.Sys().(*syscall.Stat_t)
+	if st.Uid != 1234 || st.Gid != 5678 {
+		t.Errorf("owner = %d:%d", st.Uid, st.Gid)
+	}
+}
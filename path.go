@@ -3,12 +3,22 @@ package path
 // ファイル、ディレクトリのパス文字列を扱うためのパッケージ
 
 import (
+	"bytes"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
 	"fmt"
+	"io"
+	"io/fs"
 	"os"
+	"os/exec"
 	"path/filepath"
+	"slices"
 	"sort"
 	"strconv"
 	"strings"
+	"sync"
+	"time"
 )
 
 // パス型
@@ -27,11 +37,30 @@ func GetCurDir() Path {
 	return NewPath(p)
 }
 
+// 環境変数 PATH から実行ファイルを検索
+func FindExecutable(name string) (Path, error) {
+	p, err := exec.LookPath(name)
+	if err != nil {
+		return "", err
+	}
+	return NewPath(p), nil
+}
+
 // パスを文字列に変換
 func (p Path) String() string {
 	return string(p)
 }
 
+// パスをバイト列に変換
+func (p Path) Bytes() []byte {
+	return []byte(p)
+}
+
+// バイト列からパスを作成
+func FromBytes(b []byte) Path {
+	return Path(b)
+}
+
 // 拡張子を文字列に変換
 func (e Ext) String() string {
 	return string(e)
@@ -61,6 +90,16 @@ func (p *Path) Append(element ...Path) {
 	*p = Join(append([]Path{*p}, element...)...)
 }
 
+// 冗長な区切り文字や . と .. を取り除いたパスを取得、空のパスは "." になる
+func (p Path) Clean() Path {
+	return Path(filepath.Clean(string(p)))
+}
+
+// 冗長な区切り文字や . と .. を取り除く
+func (p *Path) Normalize() {
+	*p = p.Clean()
+}
+
 // 最後の要素を取得
 func (p Path) Base() Path {
 	return Path(filepath.Base(string(p)))
@@ -71,10 +110,88 @@ func (p Path) Dir() Path {
 	return Path(filepath.Dir(string(p)))
 }
 
+// ルートから p までの各階層の累積パスを取得、p 自身も含む
+// 例: /a/b/c は /, /a, /a/b, /a/b/c となる
+func (p Path) Breadcrumbs() []Path {
+	cur := p.Clean()
+	crumbs := []Path{cur}
+	for {
+		dir := cur.Dir()
+		if dir == cur || dir == "." {
+			break
+		}
+		crumbs = append(crumbs, dir)
+		cur = dir
+	}
+	slices.Reverse(crumbs)
+	return crumbs
+}
+
+// ボリューム名を取得 (Windows のドライブ名や UNC 共有名)、Unix では空
+func (p Path) VolumeName() Path {
+	return Path(filepath.VolumeName(string(p)))
+}
+
+// ボリューム名を除いたパスを取得
+func (p Path) WithoutVolume() Path {
+	return p[len(p.VolumeName()):]
+}
+
+// 先頭から prefix を取り除く、要素の境界でのみ取り除く (/foo は /foobar にマッチしない)
+// p と prefix が等しい場合は "." を返し、マッチしない場合は p をそのまま返す
+func (p Path) TrimPrefix(prefix Path) Path {
+	pc := filepath.Clean(string(p))
+	pre := filepath.Clean(string(prefix))
+	if pc == pre {
+		return "."
+	}
+	if !strings.HasSuffix(pre, string(filepath.Separator)) {
+		pre += string(filepath.Separator)
+	}
+	if !strings.HasPrefix(pc, pre) {
+		return p
+	}
+	return Path(pc[len(pre):])
+}
+
+// 最後の要素の末尾から suffix を取り除く
+// マッチしない場合や、取り除くと最後の要素が空になる場合は p をそのまま返す
+func (p Path) TrimSuffix(suffix string) Path {
+	base := p.Base().String()
+	if !strings.HasSuffix(base, suffix) || base == suffix {
+		return p
+	}
+	return Join(p.Dir(), Path(strings.TrimSuffix(base, suffix)))
+}
+
+// 相対パスが基準ディレクトリの外側を指すか判定
+func isOutside(rel string) bool {
+	return rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// 複数のルートのうち、p を配下に含む最初のルートと、そのルートからの相対パスを取得
+// どのルートの配下にもない場合はエラー
+func (p Path) RelToFirst(roots ...Path) (Path, Path, error) {
+	for _, root := range roots {
+		rel, err := filepath.Rel(string(root), string(p))
+		if err != nil || isOutside(rel) {
+			continue
+		}
+		return root, Path(rel), nil
+	}
+	return "", "", fmt.Errorf("%s is not under any of %v", p, roots)
+}
+
 // Path が存在するか判定
+// 権限がないなど、存在しないこと以外の理由で情報を取得できない場合は true
 func (p Path) IsExist() bool {
 	_, err := os.Stat(string(p))
-	return err == nil
+	return !errors.Is(err, os.ErrNotExist)
+}
+
+// ファイル情報を取得
+func (p Path) Stat() (os.FileInfo, error) {
+	return os.Stat(string(p))
 }
 
 // Path がディレクトリか判定、存在しない場合は false
@@ -95,6 +212,43 @@ func (p Path) IsFile() bool {
 	return !fi.IsDir()
 }
 
+// ファイルサイズを取得
+// ディレクトリの場合は配下の合計ではなく、os.Stat が返すディレクトリ自体のサイズを返す
+func (p Path) Size() (int64, error) {
+	fi, err := os.Stat(string(p))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", p, err)
+	}
+	return fi.Size(), nil
+}
+
+// 更新日時を取得
+func (p Path) ModTime() (time.Time, error) {
+	fi, err := os.Stat(string(p))
+	if err != nil {
+		return time.Time{}, fmt.Errorf("%s: %w", p, err)
+	}
+	return fi.ModTime(), nil
+}
+
+// Path のディレクトリが環境変数 PATH に含まれるか判定
+func (p Path) IsInPath() bool {
+	dir, err := p.DirName().Abs()
+	if err != nil {
+		return false
+	}
+	for _, elem := range filepath.SplitList(os.Getenv("PATH")) {
+		if elem == "" {
+			continue
+		}
+		abs, err := NewPath(elem).Abs()
+		if err == nil && abs == dir {
+			return true
+		}
+	}
+	return false
+}
+
 // 絶対パスを取得
 func (p Path) Abs() (Path, error) {
 	abs, err := filepath.Abs(string(p))
@@ -104,6 +258,15 @@ func (p Path) Abs() (Path, error) {
 	return Path(abs), nil
 }
 
+// base からの相対パスを取得、p と base が等しい場合は "." を返す
+func (p Path) Rel(base Path) (Path, error) {
+	rel, err := filepath.Rel(string(base), string(p))
+	if err != nil {
+		return "", fmt.Errorf("cannot make %s relative to %s: %w", p, base, err)
+	}
+	return Path(rel), nil
+}
+
 // 絶対パスに変更
 func (p *Path) ChangeAbs() error {
 	abs, err := p.Abs()
@@ -114,12 +277,17 @@ func (p *Path) ChangeAbs() error {
 	return nil
 }
 
-// ディレクトリを作成
+// ディレクトリを作成、パーミッションは 0755
 func (p Path) CreDir() error {
+	return p.CreDirMode(0755)
+}
+
+// パーミッションを指定してディレクトリを作成
+func (p Path) CreDirMode(perm os.FileMode) error {
 	if p.IsDir() {
 		return nil
 	}
-	return os.MkdirAll(string(p), 0777)
+	return os.MkdirAll(string(p), perm)
 }
 
 // ディレクトリを削除
@@ -130,12 +298,55 @@ func (p Path) DelDir() error {
 	return os.RemoveAll(string(p))
 }
 
-// ファイルを作成
+// パーミッションを変更
+func (p Path) Chmod(mode os.FileMode) error {
+	return os.Chmod(string(p), mode)
+}
+
+// ディレクトリ配下すべてのパーミッションを再帰的に変更、シンボリックリンクは対象外
+// 実行権限のないモードでも配下を辿れるよう、ディレクトリは配下を変更した後に変更する
+// 変更できない要素があっても処理を続け、失敗したすべての要素のエラーをまとめて返す
+func (p Path) ChmodAll(mode os.FileMode) error {
+	var errs []error
+	dirs := Entries{}
+	err := filepath.WalkDir(string(p), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		switch {
+		case d.Type()&os.ModeSymlink != 0:
+		case d.IsDir():
+			dirs = append(dirs, NewPath(path))
+		default:
+			if err := NewPath(path).Chmod(mode); err != nil {
+				errs = append(errs, err)
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	// 深い階層から順に変更
+	for _, dir := range slices.Backward(dirs) {
+		if err := dir.Chmod(mode); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// ファイルを作成、親ディレクトリも作成する
 func (p Path) CreFile() (*os.File, error) {
 	if p.IsFile() {
 		// 既にファイルが存在する場合はエラー
 		return nil, os.ErrExist
 	}
+	// 親ディレクトリを作成
+	if err := p.DirName().CreDir(); err != nil {
+		return nil, err
+	}
 	// ファイルが存在しない場合は作成
 	return os.Create(string(p))
 }
@@ -148,6 +359,23 @@ func (p Path) DelFile() error {
 	return os.Remove(string(p))
 }
 
+// 存在しない場合は空のファイルを作成し、存在する場合は更新日時を現在時刻に更新する
+// 親ディレクトリは作成する。ディレクトリの場合もエラーにせず、更新日時を更新する
+func (p Path) Touch() error {
+	if p.IsExist() {
+		now := time.Now()
+		return os.Chtimes(string(p), now, now)
+	}
+	if err := p.DirName().CreDir(); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(string(p), os.O_WRONLY|os.O_CREATE, 0666)
+	if err != nil {
+		return err
+	}
+	return f.Close()
+}
+
 // ファイルを開く
 func (p Path) FileOpen() (*os.File, error) {
 	// ファイルでない場合はエラー
@@ -176,21 +404,44 @@ func (p Path) FileNameWithoutExt() Path {
 	return p.Base()[:len(p.Base())-len(p.Ext())]
 }
 
+// ファイル名を取得、連続する拡張子をすべて除く
+// 先頭のドットは拡張子とみなさない (.bashrc はそのまま)
+func (p Path) Stem() Path {
+	base := p.Base().String()
+	lead := len(base) - len(strings.TrimLeft(base, "."))
+	if i := strings.Index(base[lead:], "."); i >= 0 {
+		return NewPath(base[:lead+i])
+	}
+	return NewPath(base)
+}
+
 // ファイル名を変更、拡張子は変更しない
 func (p *Path) ChangeFileName(name Path) {
 	*p = Join(p.DirName(), Path(name.String()+p.Ext().String()))
 }
 
-// ファイル名の後ろに文字列を追加、拡張子は変更しない
+// ファイル名の前に文字列を追加 (name.ext → s + name.ext)
+// 拡張子は変更せず、拡張子がない場合はファイル名全体の前に追加する
 func (p *Path) AddPrefix(name string) {
 	*p = Join(p.DirName(), Path(name+p.FileNameWithoutExt().String()+p.Ext().String()))
 }
 
-// ファイル名の前に文字列を追加、拡張子は変更しない
+// ファイル名の後ろ、拡張子の前に文字列を追加 (name.ext → name + s + .ext)
+// 拡張子は変更せず、拡張子がない場合はファイル名全体の後ろに追加する
 func (p *Path) AddSuffix(name string) {
 	*p = Join(p.DirName(), Path(p.FileNameWithoutExt().String()+name+p.Ext().String()))
 }
 
+// ファイル名を小文字に変換したパスを取得、拡張子も含み、ディレクトリは変更しない
+func (p Path) LowerName() Path {
+	return Join(p.DirName(), Path(strings.ToLower(p.FileName().String())))
+}
+
+// ファイル名を大文字に変換したパスを取得、拡張子も含み、ディレクトリは変更しない
+func (p Path) UpperName() Path {
+	return Join(p.DirName(), Path(strings.ToUpper(p.FileName().String())))
+}
+
 // ファイル名は変更せず、ディレクトリ名を変更
 func (p *Path) ChangeDirName(dir Path) {
 	*p = Join(dir, p.FileName())
@@ -201,6 +452,22 @@ func (p *Path) AddExt(ext Ext) {
 	*p += Path(ext.String())
 }
 
+// 末尾が ext でない場合のみ拡張子を付与したパスを取得
+func (p Path) EnsureExt(ext Ext) Path {
+	if strings.HasSuffix(string(p), ext.String()) {
+		return p
+	}
+	return p + Path(ext.String())
+}
+
+// 末尾が ext でない場合のみ拡張子を付与したパスを取得、大文字小文字を区別しない
+func (p Path) EnsureExtFold(ext Ext) Path {
+	if len(p) >= len(ext) && strings.EqualFold(string(p[len(p)-len(ext):]), ext.String()) {
+		return p
+	}
+	return p + Path(ext.String())
+}
+
 // 拡張子を取得
 func (p Path) Ext() Ext {
 	return Ext(filepath.Ext(string(p)))
@@ -233,22 +500,46 @@ func (p *Path) UpperExt() {
 	p.ChangeExt(ext)
 }
 
-// ディレクトリ内のファイル、ディレクトリを取得
-func (p Path) Entries() (Entries, error) {
+// ディレクトリが空か判定、ディレクトリでない場合はエラー
+func (p Path) IsEmptyDir() (bool, error) {
+	if !p.IsDir() {
+		return false, fmt.Errorf("%s: %w", p, os.ErrNotExist)
+	}
+	dir, err := os.Open(string(p))
+	if err != nil {
+		return false, err
+	}
+	defer dir.Close()
+
+	// 1 件だけ読み込んで判定
+	_, err = dir.Readdirnames(1)
+	if err == io.EOF {
+		return true, nil
+	}
+	return false, err
+}
+
+// ディレクトリ内のファイル、ディレクトリの名前を取得、パスの結合はしない
+func (p Path) Names() ([]string, error) {
 	// ディレクトリでない場合はエラー
 	if !p.IsDir() {
-		return Entries{}, os.ErrNotExist
+		return nil, os.ErrNotExist
 	}
 
 	// ディレクトリを開く
 	dir, err := os.Open(string(p))
 	if err != nil {
-		return Entries{}, err
+		return nil, err
 	}
 	defer dir.Close()
 
-	// ディレクトリ内のファイル、ディレクトリを取得
-	names, err := dir.Readdirnames(-1)
+	// ディレクトリ内のファイル、ディレクトリの名前を取得
+	return dir.Readdirnames(-1)
+}
+
+// ディレクトリ内のファイル、ディレクトリを取得
+func (p Path) Entries() (Entries, error) {
+	names, err := p.Names()
 	if err != nil {
 		return Entries{}, err
 	}
@@ -261,6 +552,121 @@ func (p Path) Entries() (Entries, error) {
 	return entries, nil
 }
 
+// ディレクトリ内のファイル、ディレクトリを fs.DirEntry として取得、名前順
+func (p Path) DirEntries() ([]fs.DirEntry, error) {
+	// ディレクトリでない場合はエラー
+	if !p.IsDir() {
+		return nil, os.ErrNotExist
+	}
+	return os.ReadDir(string(p))
+}
+
+// ファイル名にマッチする最初のパターンを取得、マッチしない場合は false を返す
+// 不正なパターンが含まれる場合はマッチの有無に関わらずエラー
+func (p Path) FirstGlobMatch(patterns ...string) (string, bool, error) {
+	for _, pattern := range patterns {
+		if _, err := filepath.Match(pattern, ""); err != nil {
+			return "", false, fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+	}
+	base := p.Base().String()
+	for _, pattern := range patterns {
+		if ok, _ := filepath.Match(pattern, base); ok {
+			return pattern, true, nil
+		}
+	}
+	return "", false, nil
+}
+
+// ディレクトリ内でパターンにマッチするパスを取得、パターンは p からの相対パスで指定する
+// マッチしない場合は空の Entries を返す。不正なパターンの場合は filepath.ErrBadPattern を返す
+func (p Path) Glob(pattern string) (Entries, error) {
+	// マッチの有無に関わらずパターンを検証
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	// ディレクトリ名に含まれる * ? [ をパターンとして解釈させない
+	matches, err := filepath.Glob(filepath.Join(globEscape(filepath.Clean(string(p))), pattern))
+	if err != nil {
+		return nil, err
+	}
+	return ToEntries(matches), nil
+}
+
+// パターンの特殊文字をエスケープ
+// Windows ではバックスラッシュでエスケープできないため、[*] のように文字クラスで囲む
+func globEscape(s string) string {
+	var b strings.Builder
+	for _, c := range s {
+		switch {
+		case c == '*' || c == '?' || c == '[':
+			b.WriteString("[" + string(c) + "]")
+		case c == '\\' && filepath.Separator != '\\':
+			b.WriteString(`\\`)
+		default:
+			b.WriteRune(c)
+		}
+	}
+	return b.String()
+}
+
+// ディレクトリ内で複数のパターンにマッチするパスをパターンごとに取得
+// マッチしないパターンには空の Entries を設定する。不正なパターンがある場合はエラー
+func (p Path) GlobMany(patterns ...string) (map[string]Entries, error) {
+	result := make(map[string]Entries, len(patterns))
+	for _, pattern := range patterns {
+		matches, err := p.Glob(pattern)
+		if err != nil {
+			return nil, fmt.Errorf("pattern %q: %w", pattern, err)
+		}
+		result[pattern] = matches
+	}
+	return result, nil
+}
+
+// ディレクトリ内でパターンにマッチするパスを名前順に最大 n 個取得
+// 名前順にするためディレクトリ内の名前はすべて読み込むが、n 個見つかった時点でマッチングを打ち切る
+// パターンに区切り文字を含む場合は filepath.Glob の結果から先頭 n 個を返す
+func (p Path) GlobN(pattern string, n int) (Entries, error) {
+	if _, err := filepath.Match(pattern, ""); err != nil {
+		return nil, err
+	}
+	entries := Entries{}
+	if n <= 0 {
+		return entries, nil
+	}
+	if strings.ContainsRune(pattern, filepath.Separator) {
+		matches, err := p.Glob(pattern)
+		if err != nil {
+			return nil, err
+		}
+		sort.Slice(matches, func(i, j int) bool {
+			return matches[i] < matches[j]
+		})
+		return matches[:min(n, len(matches))], nil
+	}
+
+	des, err := p.DirEntries()
+	if err != nil {
+		return nil, err
+	}
+	for _, de := range des {
+		if ok, _ := filepath.Match(pattern, de.Name()); ok {
+			entries = append(entries, Join(p, NewPath(de.Name())))
+			if len(entries) == n {
+				break
+			}
+		}
+	}
+	return entries, nil
+}
+
+// ディレクトリをルートとする読み取り専用のファイルシステムを取得
+// 返り値は fs.ReadDirFS、fs.ReadFileFS、fs.StatFS を実装する
+func (p Path) FS() fs.FS {
+	return os.DirFS(string(p))
+}
+
 // ディレクトリ内のファイル、ディレクトリを取得
 func Grab(p Path) (Entries, error) {
 	return p.Entries()
@@ -275,6 +681,18 @@ func ToEntries(paths []string) Entries {
 	return entries
 }
 
+// NUL 区切りのデータ (find -print0 の出力など) から Entries に変換
+// 空の要素は除外する
+func SplitNull(data []byte) Entries {
+	entries := Entries{}
+	for _, b := range bytes.Split(data, []byte{0}) {
+		if len(b) > 0 {
+			entries = append(entries, FromBytes(b))
+		}
+	}
+	return entries
+}
+
 // Entries から抽出する一般処理
 func (e Entries) Filter(f func(Path) bool) Entries {
 	entries := Entries{}
@@ -319,6 +737,76 @@ func (e Entries) ExtractExt(exts ...Ext) Entries {
 	})
 }
 
+// Entries からディレクトリを除外、ExtractDirs の逆
+func (e Entries) ExcludeDirs() Entries {
+	return e.Filter(func(p Path) bool {
+		return !p.IsDir()
+	})
+}
+
+// Entries からファイルを除外、ExtractFiles の逆
+func (e Entries) ExcludeFiles() Entries {
+	return e.Filter(func(p Path) bool {
+		return !p.IsFile()
+	})
+}
+
+// Entries から指定の拡張子のものを除外、ExtractExt の逆
+func (e Entries) ExcludeExt(exts ...Ext) Entries {
+	return e.Filter(func(p Path) bool {
+		return !slices.Contains(exts, p.Ext())
+	})
+}
+
+// Entries から指定の拡張子のファイルのみ抽出、大文字小文字を区別しない
+func (e Entries) ExtractExtFold(exts ...Ext) Entries {
+	return e.Filter(func(p Path) bool {
+		for _, ext := range exts {
+			if strings.EqualFold(p.Ext().String(), ext.String()) {
+				return true
+			}
+		}
+		return false
+	})
+}
+
+// Entries から root の depth 階層下にあるもののみ抽出 (0 は直下)
+// root の配下にないものは除外
+func (e Entries) AtDepth(root Path, depth int) Entries {
+	return e.Filter(func(p Path) bool {
+		rel, err := filepath.Rel(string(root), string(p))
+		if err != nil || rel == "." || isOutside(rel) {
+			return false
+		}
+		return strings.Count(rel, string(filepath.Separator)) == depth
+	})
+}
+
+// Entries からリンク先が存在しないシンボリックリンクのみ抽出
+// シンボリックリンクでない要素は無視する
+func (e Entries) BrokenSymlinks() (Entries, error) {
+	entries := Entries{}
+	for _, entry := range e {
+		fi, err := os.Lstat(string(entry))
+		if errors.Is(err, os.ErrNotExist) {
+			continue
+		}
+		if err != nil {
+			return nil, err
+		}
+		if fi.Mode()&os.ModeSymlink == 0 {
+			continue
+		}
+		_, err = os.Stat(string(entry))
+		if errors.Is(err, os.ErrNotExist) {
+			entries = append(entries, entry)
+		} else if err != nil {
+			return nil, err
+		}
+	}
+	return entries, nil
+}
+
 // Entries を []string に変換
 func (e Entries) ToString() []string {
 	result := make([]string, len(e))
@@ -335,6 +823,70 @@ func (e Entries) ToAbs() (Entries, error) {
 	})
 }
 
+// Entries をすべて base からの相対パスに変換
+func (e Entries) ToRel(base Path) (Entries, error) {
+	return e.ForEachWithError(func(p Path) (Path, error) {
+		return p.Rel(base)
+	})
+}
+
+// Entries のファイルサイズの合計を取得、ディレクトリは除く
+func (e Entries) TotalSize() (int64, error) {
+	var total int64
+	for _, entry := range e {
+		fi, err := os.Stat(string(entry))
+		if err != nil {
+			return 0, fmt.Errorf("%s: %w", entry, err)
+		}
+		if !fi.IsDir() {
+			total += fi.Size()
+		}
+	}
+	return total, nil
+}
+
+// Entries のファイルを合計サイズがほぼ均等になるよう n 個のグループに分ける、ディレクトリは除く
+// サイズの大きい順に、その時点で合計が最も小さいグループへ割り当てる
+func (e Entries) PartitionBySize(n int) ([]Entries, error) {
+	if n < 1 {
+		return nil, fmt.Errorf("invalid number of groups: %d", n)
+	}
+	type sized struct {
+		path Path
+		size int64
+	}
+	files := []sized{}
+	for _, entry := range e {
+		fi, err := os.Stat(string(entry))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry, err)
+		}
+		if !fi.IsDir() {
+			files = append(files, sized{entry, fi.Size()})
+		}
+	}
+	sort.SliceStable(files, func(i, j int) bool {
+		return files[i].size > files[j].size
+	})
+
+	groups := make([]Entries, n)
+	totals := make([]int64, n)
+	for i := range groups {
+		groups[i] = Entries{}
+	}
+	for _, f := range files {
+		smallest := 0
+		for i := range totals {
+			if totals[i] < totals[smallest] {
+				smallest = i
+			}
+		}
+		groups[smallest] = append(groups[smallest], f.path)
+		totals[smallest] += f.size
+	}
+	return groups, nil
+}
+
 // Entries からファイル名のみ抽出
 func (e Entries) ToBase() Entries {
 	return e.ForEach(func(p Path) Path {
@@ -360,6 +912,72 @@ func (e Entries) ToExt() []Ext {
 	return result
 }
 
+// ファイルサイズ順に並べ替えた新しい Entries を返す
+// ディレクトリは昇順、降順に関わらず常に末尾に並べる
+func (e Entries) SortBySize(ascending bool) (Entries, error) {
+	return e.sortByInfo(func(a, b os.FileInfo) bool {
+		if a.IsDir() || b.IsDir() {
+			return !a.IsDir() && b.IsDir()
+		}
+		if ascending {
+			return a.Size() < b.Size()
+		}
+		return b.Size() < a.Size()
+	})
+}
+
+// パス全体の文字列順に並べ替えた新しい Entries を返す、元の Entries は変更しない
+func (e Entries) SortByName() Entries {
+	neu := slices.Clone(e)
+	sort.SliceStable(neu, func(i, j int) bool {
+		return neu[i] < neu[j]
+	})
+	return neu
+}
+
+// 最後の要素の文字列順に並べ替えた新しい Entries を返す、元の Entries は変更しない
+func (e Entries) SortByBase() Entries {
+	neu := slices.Clone(e)
+	sort.SliceStable(neu, func(i, j int) bool {
+		return neu[i].Base() < neu[j].Base()
+	})
+	return neu
+}
+
+// 各要素のファイル情報を一度だけ取得し、less に従って並べ替えた新しい Entries を返す
+func (e Entries) sortByInfo(less func(a, b os.FileInfo) bool) (Entries, error) {
+	infos := make([]os.FileInfo, len(e))
+	for i, entry := range e {
+		fi, err := os.Stat(string(entry))
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", entry, err)
+		}
+		infos[i] = fi
+	}
+	idx := make([]int, len(e))
+	for i := range idx {
+		idx[i] = i
+	}
+	sort.SliceStable(idx, func(i, j int) bool {
+		return less(infos[idx[i]], infos[idx[j]])
+	})
+	neu := make(Entries, len(e))
+	for i, j := range idx {
+		neu[i] = e[j]
+	}
+	return neu, nil
+}
+
+// 更新日時順に並べ替えた新しい Entries を返す
+func (e Entries) SortByModTime(ascending bool) (Entries, error) {
+	return e.sortByInfo(func(a, b os.FileInfo) bool {
+		if ascending {
+			return a.ModTime().Before(b.ModTime())
+		}
+		return b.ModTime().Before(a.ModTime())
+	})
+}
+
 // Entries 全てに共通の処理を適用して返す。
 func (e Entries) ForEach(proc func(Path) Path) Entries {
 	neu := make(Entries, len(e))
@@ -382,6 +1000,47 @@ func (e Entries) ForEachWithError(proc func(Path) (Path, error)) (Entries, error
 	return neu, nil
 }
 
+// Entries 全てに共通の処理を workers 個の並行処理で適用する。結果は返さない。
+// 最初に発生したエラーを返し、以降の要素は処理しない。
+func (e Entries) Each(workers int, proc func(Path) error) error {
+	if workers < 1 {
+		workers = 1
+	}
+	jobs := make(chan Path)
+	done := make(chan struct{})
+	var once sync.Once
+	var firstErr error
+
+	var wg sync.WaitGroup
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for p := range jobs {
+				if err := proc(p); err != nil {
+					once.Do(func() {
+						firstErr = err
+						close(done)
+					})
+				}
+			}
+		}()
+	}
+
+	// エラーが発生したら投入を中止
+feed:
+	for _, p := range e {
+		select {
+		case jobs <- p:
+		case <-done:
+			break feed
+		}
+	}
+	close(jobs)
+	wg.Wait()
+	return firstErr
+}
+
 // Entries の全ての要素がファイルであると仮定し、各ファイルのファイル名に対して処理を適用する関数
 func (e Entries) ForEachFileName(proc func(Path) Path) Entries {
 	return e.ForEach(func(p Path) Path {
@@ -391,6 +1050,16 @@ func (e Entries) ForEachFileName(proc func(Path) Path) Entries {
 	})
 }
 
+// Entries の全てのファイル名を小文字に変換して返す
+func (e Entries) LowerName() Entries {
+	return e.ForEach(Path.LowerName)
+}
+
+// Entries の全てのファイル名を大文字に変換して返す
+func (e Entries) UpperName() Entries {
+	return e.ForEach(Path.UpperName)
+}
+
 // PrependSequentialNumbers は、
 // Entries の全てのファイル名の先頭に連番を付与して更新する関数です。
 // ファイル数に応じて連番の桁数を自動設定します。
@@ -403,3 +1072,78 @@ func (e Entries) PrependSequentialNumbers() Entries {
 	})
 	return neu
 }
+
+// ランダムな名前を生成
+func randomName() string {
+	b := make([]byte, 16)
+	rand.Read(b)
+	return hex.EncodeToString(b)
+}
+
+// ディレクトリ内のランダムな名前のファイルパスを取得、ファイルは作成しない
+func (p Path) RandomFile(ext Ext) Path {
+	return Join(p, NewPath(randomName()+ext.String()))
+}
+
+// ディレクトリ内のランダムな名前のディレクトリパスを取得、ディレクトリは作成しない
+func (p Path) RandomDir() Path {
+	return Join(p, NewPath(randomName()))
+}
+
+// RenumberFiles は、
+// ディレクトリ内のファイルを名前順に並べ、prefix_001.ext のような連番の名前に実際に変更する関数です。
+// 連番の桁数は 3 桁以上でファイル数に応じて設定します。
+// ドットで始まるファイル (.DS_Store など) は対象外です。
+// 名前の衝突を避けるため、一度一時的な名前に変更してから最終的な名前に変更します。
+// 途中で失敗した場合は、それまでの変更を元に戻してエラーを返します。
+func (p Path) RenumberFiles(prefix string) (Entries, error) {
+	entries, err := p.Entries()
+	if err != nil {
+		return nil, err
+	}
+	files := entries.ExtractFiles().Filter(func(f Path) bool {
+		return !strings.HasPrefix(f.Base().String(), ".")
+	})
+	sort.Slice(files, func(i, j int) bool {
+		return files[i] < files[j]
+	})
+
+	// 完了した名前の変更を記録し、失敗時は逆順に元に戻す
+	// 元に戻せなかった場合は、そのエラーも合わせて返す
+	type rename struct{ from, to Path }
+	done := []rename{}
+	move := func(from, to Path) error {
+		if err := os.Rename(string(from), string(to)); err != nil {
+			errs := []error{err}
+			for _, r := range slices.Backward(done) {
+				if rerr := os.Rename(string(r.to), string(r.from)); rerr != nil {
+					errs = append(errs, fmt.Errorf("rollback: %w", rerr))
+				}
+			}
+			return errors.Join(errs...)
+		}
+		done = append(done, rename{from, to})
+		return nil
+	}
+
+	// 一時的な名前に変更
+	tag := randomName()
+	temps := make(Entries, len(files))
+	for i, file := range files {
+		temps[i] = Join(p, NewPath(fmt.Sprintf(".%s_%d%s", tag, i, file.Ext())))
+		if err := move(file, temps[i]); err != nil {
+			return nil, err
+		}
+	}
+
+	// 最終的な名前に変更
+	digits := max(len(strconv.Itoa(len(files))), 3)
+	renamed := make(Entries, len(temps))
+	for i, temp := range temps {
+		renamed[i] = Join(p, NewPath(fmt.Sprintf("%s_%0*d%s", prefix, digits, i+1, temp.Ext())))
+		if err := move(temp, renamed[i]); err != nil {
+			return nil, err
+		}
+	}
+	return renamed, nil
+}
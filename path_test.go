@@ -0,0 +1,699 @@
+package path
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+// テスト用のファイルを作成
+func writeTestFile(t *testing.T, p Path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(p.DirName().String(), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(p.String(), []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// テスト用のファイルの内容を読み込む
+func readTestFile(t *testing.T, p Path) string {
+	t.Helper()
+	data, err := os.ReadFile(p.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	return string(data)
+}
+
+// スラッシュ区切りのパスを OS の区切り文字に変換
+func fromSlash(s string) Path {
+	return NewPath(filepath.FromSlash(s))
+}
+
+// テスト用のディレクトリツリーを作成
+func buildTestTree(t *testing.T, files map[string]string) Path {
+	t.Helper()
+	root := NewPath(t.TempDir())
+	for name, content := range files {
+		writeTestFile(t, Join(root, fromSlash(name)), content)
+	}
+	return root
+}
+
+func TestRandomFile(t *testing.T) {
+	dir := NewPath(t.TempDir())
+	seen := map[Path]bool{}
+	for range 1000 {
+		p := dir.RandomFile(".txt")
+		if p.Ext() != ".txt" || p.Dir() != dir {
+			t.Fatalf("RandomFile = %q", p)
+		}
+		if seen[p] {
+			t.Fatalf("duplicate name %q", p)
+		}
+		seen[p] = true
+	}
+	if d := dir.RandomDir(); d.Dir() != dir || d.Ext() != "" {
+		t.Errorf("RandomDir = %q", d)
+	}
+}
+
+func TestStem(t *testing.T) {
+	tests := []struct {
+		in, want Path
+	}{
+		{"archive.tar.gz", "archive"},
+		{fromSlash("dir/archive.tar.gz"), "archive"},
+		{".bashrc", ".bashrc"},
+		{".config.tar.gz", ".config"},
+		{"README", "README"},
+	}
+	for _, tt := range tests {
+		if got := tt.in.Stem(); got != tt.want {
+			t.Errorf("%q.Stem() = %q, want %q", tt.in, got, tt.want)
+		}
+	}
+}
+
+func TestBytes(t *testing.T) {
+	p := fromSlash("a/b c")
+	if FromBytes(p.Bytes()) != p {
+		t.Error("Bytes round-trip failed")
+	}
+	got := SplitNull([]byte("a\x00b c\x00"))
+	if !slices.Equal(got, Entries{"a", "b c"}) {
+		t.Errorf("SplitNull = %v", got)
+	}
+	if got := SplitNull(nil); len(got) != 0 {
+		t.Errorf("SplitNull(empty) = %v", got)
+	}
+}
+
+func TestEach(t *testing.T) {
+	entries := Entries{"a", "b", "c", "d", "e"}
+	var mu sync.Mutex
+	seen := map[Path]bool{}
+	err := entries.Each(3, func(p Path) error {
+		mu.Lock()
+		defer mu.Unlock()
+		seen[p] = true
+		return nil
+	})
+	if err != nil || len(seen) != len(entries) {
+		t.Errorf("Each processed %d entries, err = %v", len(seen), err)
+	}
+
+	errBoom := errors.New("boom")
+	err = entries.Each(2, func(p Path) error {
+		if p == "c" {
+			return errBoom
+		}
+		return nil
+	})
+	if !errors.Is(err, errBoom) {
+		t.Errorf("Each error = %v", err)
+	}
+}
+
+func TestDirEntries(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "", "sub/b.txt": ""})
+	des, err := root.DirEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(des) != 2 || des[0].Name() != "a.txt" || des[0].IsDir() || des[1].Name() != "sub" || !des[1].IsDir() {
+		t.Errorf("DirEntries = %v", des)
+	}
+	if _, err := Join(root, "none").DirEntries(); err == nil {
+		t.Error("DirEntries(missing) should fail")
+	}
+}
+
+func TestVolumeName(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		p := NewPath(`C:\dir\file.txt`)
+		if p.VolumeName() != "C:" || p.WithoutVolume() != `\dir\file.txt` {
+			t.Errorf("VolumeName = %q, WithoutVolume = %q", p.VolumeName(), p.WithoutVolume())
+		}
+		return
+	}
+	p := NewPath("/usr/bin")
+	if p.VolumeName() != "" || p.WithoutVolume() != p {
+		t.Errorf("VolumeName = %q, WithoutVolume = %q", p.VolumeName(), p.WithoutVolume())
+	}
+}
+
+func TestGlobMany(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"app-1.log": "", "app-2.log": "", "other.log": "", "x.txt": ""})
+	got, err := root.GlobMany("*.log", "*.md")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(got) != 2 || len(got["*.log"]) != 3 || len(got["*.md"]) != 0 {
+		t.Errorf("GlobMany = %v", got)
+	}
+	if _, err := root.GlobMany("*.log", "[bad"); err == nil || !strings.Contains(err.Error(), "[bad") {
+		t.Errorf("GlobMany(bad) = %v", err)
+	}
+}
+
+func TestBrokenSymlinks(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"target": "", "gone": ""})
+	good, bad := Join(root, "good"), Join(root, "bad")
+	if err := os.Symlink("target", good.String()); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	if err := os.Symlink("gone", bad.String()); err != nil {
+		t.Fatal(err)
+	}
+	os.Remove(Join(root, "gone").String())
+
+	got, err := Entries{good, bad, Join(root, "target"), Join(root, "none")}.BrokenSymlinks()
+	if err != nil || !slices.Equal(got, Entries{bad}) {
+		t.Errorf("BrokenSymlinks = %v, %v", got, err)
+	}
+}
+
+// ディレクトリ内の名前を並べて取得
+func sortedNames(t *testing.T, dir Path) []string {
+	t.Helper()
+	des, err := os.ReadDir(dir.String())
+	if err != nil {
+		t.Fatal(err)
+	}
+	names := make([]string, len(des))
+	for i, de := range des {
+		names[i] = de.Name()
+	}
+	return names
+}
+
+func TestRenumberFiles(t *testing.T) {
+	root := buildTestTree(t, map[string]string{
+		"c.jpg": "c", "a.jpg": "a", "b.png": "b", ".hidden": "h", "sub/x.jpg": "",
+	})
+	got, err := root.RenumberFiles("img")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Entries{Join(root, "img_001.jpg"), Join(root, "img_002.png"), Join(root, "img_003.jpg")}
+	if !slices.Equal(got, want) {
+		t.Errorf("RenumberFiles = %v, want %v", got, want)
+	}
+	for i, content := range []string{"a", "b", "c"} {
+		if s := readTestFile(t, want[i]); s != content {
+			t.Errorf("%s = %q, want %q", want[i], s, content)
+		}
+	}
+	if names := sortedNames(t, root); !slices.Equal(names, []string{".hidden", "img_001.jpg", "img_002.png", "img_003.jpg", "sub"}) {
+		t.Errorf("names after renumber = %v", names)
+	}
+}
+
+func TestRenumberFilesRollback(t *testing.T) {
+	// 変更先の名前が空でないディレクトリのため、2 つ目の名前の変更に失敗する
+	root := buildTestTree(t, map[string]string{"a.jpg": "a", "b.png": "b", "img_002.png/keep": ""})
+	if _, err := root.RenumberFiles("img"); err == nil {
+		t.Fatal("RenumberFiles should fail")
+	}
+	if names := sortedNames(t, root); !slices.Equal(names, []string{"a.jpg", "b.png", "img_002.png"}) {
+		t.Errorf("names after rollback = %v", names)
+	}
+	if s := readTestFile(t, Join(root, "a.jpg")); s != "a" {
+		t.Errorf("a.jpg = %q", s)
+	}
+}
+
+func TestTrimPrefix(t *testing.T) {
+	tests := []struct {
+		p, prefix, want string
+	}{
+		{"/foo/bar/baz", "/foo", "bar/baz"},
+		{"/foo/bar", "/foo/", "bar"},
+		{"/foobar", "/foo", "/foobar"},
+		{"/foo", "/foo", "."},
+		{"/other/x", "/foo", "/other/x"},
+	}
+	for _, tt := range tests {
+		if got := fromSlash(tt.p).TrimPrefix(fromSlash(tt.prefix)); got != fromSlash(tt.want) {
+			t.Errorf("%q.TrimPrefix(%q) = %q, want %q", tt.p, tt.prefix, got, tt.want)
+		}
+	}
+}
+
+func TestTrimSuffix(t *testing.T) {
+	if got := fromSlash("dir/photo_copy.jpg").TrimSuffix(".jpg"); got != fromSlash("dir/photo_copy") {
+		t.Errorf("TrimSuffix = %q", got)
+	}
+	if got := fromSlash("dir/a.txt").TrimSuffix(".md"); got != fromSlash("dir/a.txt") {
+		t.Errorf("TrimSuffix(no match) = %q", got)
+	}
+}
+
+func TestGlobN(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"c.log": "", "a.log": "", "b.log": "", "x.txt": ""})
+	got, err := root.GlobN("*.log", 2)
+	if err != nil || !slices.Equal(got, Entries{Join(root, "a.log"), Join(root, "b.log")}) {
+		t.Errorf("GlobN = %v, %v", got, err)
+	}
+	got, err = root.GlobN("*.md", 2)
+	if err != nil || len(got) != 0 {
+		t.Errorf("GlobN(no match) = %v, %v", got, err)
+	}
+	if _, err := root.GlobN("[", 1); err == nil {
+		t.Error("GlobN(bad) should fail")
+	}
+}
+
+func TestFS(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "hello", "sub/b.txt": ""})
+	fsys := root.FS()
+	data, err := fs.ReadFile(fsys, "a.txt")
+	if err != nil || string(data) != "hello" {
+		t.Errorf("ReadFile = %q, %v", data, err)
+	}
+	des, err := fs.ReadDir(fsys, ".")
+	if err != nil || len(des) != 2 {
+		t.Errorf("ReadDir = %v, %v", des, err)
+	}
+	if _, ok := fsys.(fs.ReadDirFS); !ok {
+		t.Error("FS does not implement fs.ReadDirFS")
+	}
+	if _, ok := fsys.(fs.StatFS); !ok {
+		t.Error("FS does not implement fs.StatFS")
+	}
+}
+
+func TestFindExecutable(t *testing.T) {
+	p, err := FindExecutable("go")
+	if err != nil {
+		t.Skip("go not in PATH")
+	}
+	if !p.IsFile() || !p.IsInPath() {
+		t.Errorf("FindExecutable = %q, IsInPath = %v", p, p.IsInPath())
+	}
+	if _, err := FindExecutable("no-such-command-xyz"); err == nil {
+		t.Error("FindExecutable(missing) should fail")
+	}
+	if NewPath(t.TempDir()).RandomFile("").IsInPath() {
+		t.Error("temp dir should not be in PATH")
+	}
+}
+
+func TestNames(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "", "sub/b.txt": ""})
+	names, err := root.Names()
+	if err != nil {
+		t.Fatal(err)
+	}
+	slices.Sort(names)
+	if !slices.Equal(names, []string{"a.txt", "sub"}) {
+		t.Errorf("Names = %v", names)
+	}
+	if _, err := Join(root, "a.txt").Names(); err == nil {
+		t.Error("Names on a file should fail")
+	}
+}
+
+func TestLowerUpperName(t *testing.T) {
+	if got := fromSlash("Dir/File.TXT").LowerName(); got != fromSlash("Dir/file.txt") {
+		t.Errorf("LowerName = %q", got)
+	}
+	if got := fromSlash("Dir/File.txt").UpperName(); got != fromSlash("Dir/FILE.TXT") {
+		t.Errorf("UpperName = %q", got)
+	}
+	got := Entries{fromSlash("A/B.JPG"), "C.Png"}.LowerName()
+	if !slices.Equal(got, Entries{fromSlash("A/b.jpg"), "c.png"}) {
+		t.Errorf("Entries.LowerName = %v", got)
+	}
+}
+
+func TestAtDepth(t *testing.T) {
+	root := fromSlash("/root")
+	entries := Entries{
+		Join(root, "a.txt"), Join(root, "sub"), Join(root, "sub", "b.txt"),
+		Join(root, "sub", "deep"), Join(root, "sub", "deep", "c.txt"),
+	}
+	if got := entries.AtDepth(root, 0); !slices.Equal(got, Entries{Join(root, "a.txt"), Join(root, "sub")}) {
+		t.Errorf("AtDepth(0) = %v", got)
+	}
+	if got := entries.AtDepth(root, 1); !slices.Equal(got, Entries{Join(root, "sub", "b.txt"), Join(root, "sub", "deep")}) {
+		t.Errorf("AtDepth(1) = %v", got)
+	}
+	if got := (Entries{fromSlash("/elsewhere/x"), root}).AtDepth(root, 0); len(got) != 0 {
+		t.Errorf("AtDepth(outside) = %v", got)
+	}
+}
+
+func TestRelToFirst(t *testing.T) {
+	root, rel, err := fromSlash("/src/two/pkg/f.go").RelToFirst(fromSlash("/src/one"), fromSlash("/src/two"), fromSlash("/src"))
+	if err != nil || root != fromSlash("/src/two") || rel != fromSlash("pkg/f.go") {
+		t.Errorf("RelToFirst = %q, %q, %v", root, rel, err)
+	}
+	if _, _, err := fromSlash("/elsewhere/f").RelToFirst(fromSlash("/src")); err == nil {
+		t.Error("RelToFirst outside roots should fail")
+	}
+}
+
+func TestEnsureExt(t *testing.T) {
+	if got := NewPath("file").EnsureExt(".txt"); got != "file.txt" {
+		t.Errorf("EnsureExt = %q", got)
+	}
+	if got := NewPath("file.txt").EnsureExt(".txt"); got != "file.txt" {
+		t.Errorf("EnsureExt = %q", got)
+	}
+	if got := NewPath("file.TXT").EnsureExt(".txt"); got != "file.TXT.txt" {
+		t.Errorf("EnsureExt = %q", got)
+	}
+	if got := NewPath("file.TXT").EnsureExtFold(".txt"); got != "file.TXT" {
+		t.Errorf("EnsureExtFold = %q", got)
+	}
+}
+
+func TestGlob(t *testing.T) {
+	// ディレクトリ名にパターンの特殊文字を含む
+	root := Join(NewPath(t.TempDir()), "a[1]")
+	for _, name := range []string{"app-1.log", "app-2.log", "other.log", "x.txt"} {
+		writeTestFile(t, Join(root, NewPath(name)), "")
+	}
+
+	got, err := root.Glob("app-*.log")
+	if err != nil || !slices.Equal(got.ToBase(), Entries{"app-1.log", "app-2.log"}) {
+		t.Errorf("Glob = %v, %v", got, err)
+	}
+	got, err = root.Glob("*.none")
+	if err != nil || got == nil || len(got) != 0 {
+		t.Errorf("Glob(no match) = %#v, %v", got, err)
+	}
+	if _, err := root.Glob("["); !errors.Is(err, filepath.ErrBadPattern) {
+		t.Errorf("Glob(bad) = %v", err)
+	}
+}
+
+func TestRel(t *testing.T) {
+	rel, err := fromSlash("/a/b/c").Rel(fromSlash("/a"))
+	if err != nil || rel != fromSlash("b/c") {
+		t.Errorf("Rel = %q, %v", rel, err)
+	}
+	rel, err = fromSlash("/a").Rel(fromSlash("/a"))
+	if err != nil || rel != "." {
+		t.Errorf("Rel(same) = %q, %v", rel, err)
+	}
+	if _, err := NewPath("rel").Rel(fromSlash("/abs")); err == nil {
+		t.Error("Rel(relative, absolute) should fail")
+	}
+	got, err := Entries{fromSlash("/a/x"), fromSlash("/a/y/z")}.ToRel(fromSlash("/a"))
+	if err != nil || !slices.Equal(got, Entries{"x", fromSlash("y/z")}) {
+		t.Errorf("ToRel = %v, %v", got, err)
+	}
+}
+
+func TestClean(t *testing.T) {
+	if got := NewPath("").Clean(); got != "." {
+		t.Errorf("Clean(empty) = %q", got)
+	}
+	p := fromSlash("a//b/../c")
+	p.Normalize()
+	if p != fromSlash("a/c") {
+		t.Errorf("Normalize = %q", p)
+	}
+}
+
+func TestSizeAndTotalSize(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a": "12345", "b": "123", "sub/c": "1"})
+	size, err := Join(root, "a").Size()
+	if err != nil || size != 5 {
+		t.Errorf("Size = %d, %v", size, err)
+	}
+	total, err := Entries{Join(root, "a"), Join(root, "b"), Join(root, "sub")}.TotalSize()
+	if err != nil || total != 8 {
+		t.Errorf("TotalSize = %d, %v", total, err)
+	}
+	if _, err := Join(root, "none").Size(); err == nil {
+		t.Error("Size(missing) should fail")
+	}
+}
+
+func TestFirstGlobMatch(t *testing.T) {
+	p := fromSlash("dir/report.final.pdf")
+	pattern, ok, err := p.FirstGlobMatch("*.txt", "*.pdf", "report.*")
+	if err != nil || !ok || pattern != "*.pdf" {
+		t.Errorf("FirstGlobMatch = %q, %v, %v", pattern, ok, err)
+	}
+	if _, ok, _ := p.FirstGlobMatch("*.doc"); ok {
+		t.Error("FirstGlobMatch should not match")
+	}
+	if _, _, err := p.FirstGlobMatch("*.pdf", "["); err == nil {
+		t.Error("FirstGlobMatch(bad) should fail")
+	}
+}
+
+func TestModTime(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"b/z": "", "a/y": "", "c/x": ""})
+	e := Entries{Join(root, "b", "z"), Join(root, "a", "y"), Join(root, "c", "x")}
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i, p := range e {
+		mt := base.Add(time.Duration(i) * time.Minute)
+		os.Chtimes(p.String(), mt, mt)
+	}
+	if mt, err := e[1].ModTime(); err != nil || !mt.Equal(base.Add(time.Minute)) {
+		t.Errorf("ModTime = %v, %v", mt, err)
+	}
+
+	got, err := e.SortByModTime(false)
+	if err != nil || !slices.Equal(got, Entries{e[2], e[1], e[0]}) {
+		t.Errorf("SortByModTime(desc) = %v, %v", got, err)
+	}
+	got, err = e.SortByModTime(true)
+	if err != nil || !slices.Equal(got, e) {
+		t.Errorf("SortByModTime(asc) = %v, %v", got, err)
+	}
+	if _, err := (Entries{Join(root, "none")}).SortByModTime(true); err == nil || !strings.Contains(err.Error(), "none") {
+		t.Errorf("SortByModTime(missing) = %v", err)
+	}
+}
+
+func TestSortByName(t *testing.T) {
+	root := fromSlash("/root")
+	e := Entries{Join(root, "b", "z"), Join(root, "a", "y"), Join(root, "c", "x")}
+	orig := slices.Clone(e)
+
+	if got := e.SortByName(); !slices.Equal(got, Entries{e[1], e[0], e[2]}) {
+		t.Errorf("SortByName = %v", got)
+	}
+	if got := e.SortByBase(); !slices.Equal(got, Entries{e[2], e[1], e[0]}) {
+		t.Errorf("SortByBase = %v", got)
+	}
+	if !slices.Equal(e, orig) {
+		t.Error("sorting mutated the receiver")
+	}
+}
+
+func TestSortBySize(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"b/z": "1", "a/y": "333", "c/x": "22"})
+	e := Entries{Join(root, "b", "z"), Join(root, "a", "y"), Join(root, "c", "x"), Join(root, "a")}
+	orig := slices.Clone(e)
+
+	// ディレクトリは末尾に並べる
+	got, err := e.SortBySize(false)
+	if err != nil || !slices.Equal(got, Entries{e[1], e[2], e[0], e[3]}) {
+		t.Errorf("SortBySize(desc) = %v, %v", got, err)
+	}
+	got, err = e.SortBySize(true)
+	if err != nil || !slices.Equal(got, Entries{e[0], e[2], e[1], e[3]}) {
+		t.Errorf("SortBySize(asc) = %v, %v", got, err)
+	}
+	if !slices.Equal(e, orig) {
+		t.Error("sorting mutated the receiver")
+	}
+}
+
+func TestTouch(t *testing.T) {
+	root := NewPath(t.TempDir())
+	p := Join(root, "a", "b.txt")
+	if err := p.Touch(); err != nil {
+		t.Fatal(err)
+	}
+	if !p.IsFile() {
+		t.Fatal("Touch did not create the file")
+	}
+	old := time.Now().Add(-time.Hour)
+	for _, target := range []Path{p, root} {
+		os.Chtimes(target.String(), old, old)
+		if err := target.Touch(); err != nil {
+			t.Fatal(err)
+		}
+		mt, _ := target.ModTime()
+		if !mt.After(old.Add(time.Minute)) {
+			t.Errorf("Touch(%s) did not update mtime", target)
+		}
+	}
+}
+
+func TestChmodAll(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "", "sub/b.txt": "", "sub/deep/c.txt": ""})
+	t.Cleanup(func() { Join(root, "sub").ChmodAll(0755) })
+	if err := Join(root, "sub").ChmodAll(0700); err != nil {
+		t.Fatal(err)
+	}
+	for _, p := range []Path{Join(root, "sub"), Join(root, "sub", "b.txt"), Join(root, "sub", "deep"), Join(root, "sub", "deep", "c.txt")} {
+		fi, err := os.Stat(p.String())
+		if err != nil {
+			t.Fatal(err)
+		}
+		if fi.Mode().Perm() != 0700 {
+			t.Errorf("%s perm = %v", p, fi.Mode().Perm())
+		}
+	}
+	if err := Join(root, "none").Chmod(0600); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Chmod(missing) = %v", err)
+	}
+}
+
+func TestCreDirMode(t *testing.T) {
+	dir := Join(NewPath(t.TempDir()), "d", "e")
+	if err := dir.CreDirMode(0700); err != nil {
+		t.Fatal(err)
+	}
+	fi, _ := os.Stat(dir.String())
+	if !fi.IsDir() || fi.Mode().Perm() != 0700 {
+		t.Errorf("CreDirMode mode = %v", fi.Mode())
+	}
+}
+
+func TestCreFile(t *testing.T) {
+	file := Join(NewPath(t.TempDir()), "x", "y", "f.txt")
+	f, err := file.CreFile()
+	if err != nil {
+		t.Fatal(err)
+	}
+	f.Close()
+	if _, err := file.CreFile(); !errors.Is(err, os.ErrExist) {
+		t.Errorf("CreFile(existing) = %v", err)
+	}
+}
+
+func TestBreadcrumbs(t *testing.T) {
+	got := fromSlash("/a/b/c").Breadcrumbs()
+	want := []Path{fromSlash("/"), fromSlash("/a"), fromSlash("/a/b"), fromSlash("/a/b/c")}
+	if !slices.Equal(got, want) {
+		t.Errorf("Breadcrumbs = %v, want %v", got, want)
+	}
+	got = fromSlash("a/b").Breadcrumbs()
+	if !slices.Equal(got, []Path{"a", fromSlash("a/b")}) {
+		t.Errorf("Breadcrumbs(relative) = %v", got)
+	}
+}
+
+func TestIsExistAndStat(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "abc"})
+	if !Join(root, "a.txt").IsExist() || Join(root, "none").IsExist() {
+		t.Error("IsExist returned wrong result")
+	}
+	fi, err := Join(root, "a.txt").Stat()
+	if err != nil || fi.Size() != 3 {
+		t.Errorf("Stat = %v, %v", fi, err)
+	}
+	if _, err := Join(root, "none").Stat(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("Stat(missing) = %v", err)
+	}
+}
+
+func TestIsEmptyDir(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"sub/a.txt": ""})
+	Join(root, "empty").CreDir()
+	if empty, err := Join(root, "empty").IsEmptyDir(); err != nil || !empty {
+		t.Errorf("IsEmptyDir(empty) = %v, %v", empty, err)
+	}
+	if empty, err := Join(root, "sub").IsEmptyDir(); err != nil || empty {
+		t.Errorf("IsEmptyDir(sub) = %v, %v", empty, err)
+	}
+	if _, err := Join(root, "sub", "a.txt").IsEmptyDir(); err == nil {
+		t.Error("IsEmptyDir on a file should fail")
+	}
+}
+
+func TestAddPrefixSuffix(t *testing.T) {
+	tests := []struct {
+		in, prefix, suffix string
+	}{
+		{"dir/name.txt", "dir/pre_name.txt", "dir/name_suf.txt"},
+		{"dir/README", "dir/pre_README", "dir/README_suf"},
+	}
+	for _, tt := range tests {
+		p := fromSlash(tt.in)
+		p.AddPrefix("pre_")
+		if p != fromSlash(tt.prefix) {
+			t.Errorf("AddPrefix(%q) = %q, want %q", tt.in, p, tt.prefix)
+		}
+		p = fromSlash(tt.in)
+		p.AddSuffix("_suf")
+		if p != fromSlash(tt.suffix) {
+			t.Errorf("AddSuffix(%q) = %q, want %q", tt.in, p, tt.suffix)
+		}
+	}
+}
+
+func TestPartitionBySize(t *testing.T) {
+	root := buildTestTree(t, map[string]string{
+		"a": strings.Repeat("x", 50),
+		"b": strings.Repeat("x", 40),
+		"c": strings.Repeat("x", 30),
+		"d": strings.Repeat("x", 20),
+	})
+	entries, _ := root.Entries()
+	groups, err := entries.PartitionBySize(2)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(groups) != 2 {
+		t.Fatalf("got %d groups", len(groups))
+	}
+	for _, g := range groups {
+		total, _ := g.TotalSize()
+		if total != 70 {
+			t.Errorf("group %v total = %d, want 70", g, total)
+		}
+	}
+	if _, err := entries.PartitionBySize(0); err == nil {
+		t.Error("PartitionBySize(0) should fail")
+	}
+}
+
+func TestExtractExtFold(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.JPG": "", "b.jpg": "", "c.tmp": "", "sub/d.jpg": ""})
+	entries, _ := root.Entries()
+	entries = entries.SortByName()
+
+	if got := entries.ExtractExtFold(".jpg").ToBase(); !slices.Equal(got, Entries{"a.JPG", "b.jpg"}) {
+		t.Errorf("ExtractExtFold = %v", got)
+	}
+	if got := entries.ExtractExtFold(".tmp", ".JPG").ToBase(); !slices.Equal(got, Entries{"a.JPG", "b.jpg", "c.tmp"}) {
+		t.Errorf("ExtractExtFold(multiple) = %v", got)
+	}
+}
+
+func TestExclude(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.JPG": "", "b.jpg": "", "c.tmp": "", "sub/d.txt": ""})
+	entries, _ := root.Entries()
+	entries = entries.SortByName()
+
+	if got := entries.ExcludeExt(".tmp", ".jpg").ToBase(); !slices.Equal(got, Entries{"a.JPG", "sub"}) {
+		t.Errorf("ExcludeExt = %v", got)
+	}
+	if got := entries.ExcludeDirs().ToBase(); !slices.Equal(got, Entries{"a.JPG", "b.jpg", "c.tmp"}) {
+		t.Errorf("ExcludeDirs = %v", got)
+	}
+	if got := entries.ExcludeFiles().ToBase(); !slices.Equal(got, Entries{"sub"}) {
+		t.Errorf("ExcludeFiles = %v", got)
+	}
+}
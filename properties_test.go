@@ -0,0 +1,45 @@
+package path
+
+import (
+	"maps"
+	"strings"
+	"testing"
+)
+
+func TestReadProperties(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "app.properties")
+	writeTestFile(t, p, strings.Join([]string{
+		"# comment",
+		"! another comment",
+		"",
+		"a=1",
+		"b : 2",
+		"c 3",
+		"  d\t=  spaced value",
+		"multi = one, \\",
+		"        two",
+		"key\\ with\\ spaces = x",
+		"unicode = \\u3042",
+		"path = C:\\\\dir",
+		"empty",
+	}, "\n"))
+
+	got, err := p.ReadProperties()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := map[string]string{
+		"a": "1", "b": "2", "c": "3", "d": "spaced value",
+		"multi": "one, two", "key with spaces": "x",
+		"unicode": "あ", "path": `C:\dir`, "empty": "",
+	}
+	if !maps.Equal(got, want) {
+		t.Errorf("ReadProperties = %q, want %q", got, want)
+	}
+
+	writeTestFile(t, p, "ok=1\nbad=\\uZZZZ\n")
+	_, err = p.ReadProperties()
+	if err == nil || !strings.Contains(err.Error(), ":2:") {
+		t.Errorf("ReadProperties(invalid) = %v", err)
+	}
+}
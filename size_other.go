@@ -0,0 +1,11 @@
+//go:build !unix
+
+package path
+
+// ディスク上の使用量の取得 (Unix 以外)
+
+// ディスク上に実際に割り当てられているサイズを取得
+// ブロック数を取得できないため、Size と同じ論理サイズを返す
+func (p Path) AllocatedSize() (int64, error) {
+	return p.Size()
+}
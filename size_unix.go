@@ -0,0 +1,25 @@
+//go:build unix
+
+package path
+
+// ディスク上の使用量の取得 (Unix)
+
+import (
+	"fmt"
+	"os"
+	"syscall"
+)
+
+// ディスク上に実際に割り当てられているサイズを取得
+// スパースファイルや圧縮されたファイルでは Size が返す論理サイズと異なる
+func (p Path) AllocatedSize() (int64, error) {
+	fi, err := os.Stat(string(p))
+	if err != nil {
+		return 0, fmt.Errorf("%s: %w", p, err)
+	}
+	st, ok := fi.Sys().(*syscall.Stat_t)
+	if !ok {
+		return fi.Size(), nil
+	}
+	return int64(st.Blocks) * 512, nil
+}
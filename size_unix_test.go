@@ -0,0 +1,32 @@
+//go:build unix
+
+package path
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestAllocatedSize(t *testing.T) {
+	root := NewPath(t.TempDir())
+	sparse := Join(root, "sparse.bin")
+	if err := sparse.CreateSized(64 << 20); err != nil {
+		t.Fatal(err)
+	}
+	allocated, err := sparse.AllocatedSize()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if allocated >= 64<<20 {
+		t.Skip("file system does not support sparse files")
+	}
+
+	dense := Join(root, "dense.bin")
+	writeTestFile(t, dense, strings.Repeat("x", 64<<10))
+	if allocated, err := dense.AllocatedSize(); err != nil || allocated < 64<<10 {
+		t.Errorf("AllocatedSize(dense) = %d, %v", allocated, err)
+	}
+	if _, err := Join(root, "none").AllocatedSize(); err == nil {
+		t.Error("AllocatedSize(missing) should fail")
+	}
+}
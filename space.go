@@ -0,0 +1,28 @@
+package path
+
+// ディスクの空き容量を扱う処理
+
+// 空き容量の判定に使う余裕分 (1MiB)
+const spaceMargin = 1 << 20
+
+// p のファイルシステムに bytes バイトと余裕分を書き込める空き容量があるか判定
+// p が存在しない場合は、存在する最も近い親ディレクトリのファイルシステムで判定する
+// Linux、macOS、FreeBSD、Windows 以外では errors.ErrUnsupported を返す
+func (p Path) HasSpaceFor(bytes int64) (bool, error) {
+	dir := p
+	for !dir.IsExist() {
+		parent := dir.Dir()
+		if parent == dir {
+			break
+		}
+		dir = parent
+	}
+	free, err := freeSpace(dir)
+	if err != nil {
+		return false, err
+	}
+	if bytes < 0 {
+		bytes = 0
+	}
+	return uint64(bytes)+spaceMargin <= free, nil
+}
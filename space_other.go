@@ -0,0 +1,14 @@
+//go:build !(linux || darwin || freebsd || windows)
+
+package path
+
+// ディスクの空き容量の取得 (未対応のプラットフォーム)
+
+import (
+	"errors"
+)
+
+// ファイルシステムの空き容量を取得、未対応のためエラーを返す
+func freeSpace(p Path) (uint64, error) {
+	return 0, errors.ErrUnsupported
+}
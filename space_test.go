@@ -0,0 +1,20 @@
+package path
+
+import (
+	"errors"
+	"testing"
+)
+
+func TestHasSpaceFor(t *testing.T) {
+	root := NewPath(t.TempDir())
+	ok, err := Join(root, "not", "yet", "created").HasSpaceFor(0)
+	if errors.Is(err, errors.ErrUnsupported) {
+		t.Skip(err)
+	}
+	if err != nil || !ok {
+		t.Errorf("HasSpaceFor(0) = %v, %v", ok, err)
+	}
+	if ok, err := root.HasSpaceFor(1 << 62); err != nil || ok {
+		t.Errorf("HasSpaceFor(huge) = %v, %v", ok, err)
+	}
+}
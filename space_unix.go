@@ -0,0 +1,18 @@
+//go:build linux || darwin || freebsd
+
+package path
+
+// ディスクの空き容量の取得 (Unix)
+
+import (
+	"syscall"
+)
+
+// ファイルシステムの一般ユーザーが使える空き容量を取得
+func freeSpace(p Path) (uint64, error) {
+	var st syscall.Statfs_t
+	if err := syscall.Statfs(string(p), &st); err != nil {
+		return 0, err
+	}
+	return uint64(st.Bavail) * uint64(st.Bsize), nil
+}
@@ -0,0 +1,22 @@
+//go:build windows
+
+package path
+
+// ディスクの空き容量の取得 (Windows)
+
+import (
+	"golang.org/x/sys/windows"
+)
+
+// ファイルシステムの呼び出し元のユーザーが使える空き容量を取得
+func freeSpace(p Path) (uint64, error) {
+	name, err := windows.UTF16PtrFromString(string(p))
+	if err != nil {
+		return 0, err
+	}
+	var free uint64
+	if err := windows.GetDiskFreeSpaceEx(name, &free, nil, nil); err != nil {
+		return 0, err
+	}
+	return free, nil
+}
@@ -0,0 +1,157 @@
+package path
+
+// ディレクトリツリーを辿る処理
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// ディレクトリ配下のファイル、ディレクトリを再帰的に取得
+// 各ディレクトリ内ではディレクトリを先、ファイルを後にし、それぞれ名前順に並べる
+// ディレクトリの直後にはその配下の要素が続く
+func (p Path) ListDeep() (Entries, error) {
+	if !p.IsDir() {
+		return Entries{}, os.ErrNotExist
+	}
+	entries := Entries{}
+	if err := listDeep(p, &entries); err != nil {
+		return Entries{}, err
+	}
+	return entries, nil
+}
+
+// ListDeep の再帰処理
+func listDeep(dir Path, entries *Entries) error {
+	des, err := os.ReadDir(string(dir))
+	if err != nil {
+		return err
+	}
+	// os.ReadDir は名前順なので、ディレクトリとファイルに分けるだけでよい
+	files := Entries{}
+	for _, de := range des {
+		child := Join(dir, NewPath(de.Name()))
+		if !de.IsDir() {
+			files = append(files, child)
+			continue
+		}
+		*entries = append(*entries, child)
+		if err := listDeep(child, entries); err != nil {
+			return err
+		}
+	}
+	*entries = append(*entries, files...)
+	return nil
+}
+
+// ディレクトリ配下のファイルを再帰的に辿り、拡張子ごとの数を集計
+func (p Path) CountByExt() (map[Ext]int, error) {
+	return p.countByExt(func(ext Ext) Ext { return ext })
+}
+
+// ディレクトリ配下のファイルを再帰的に辿り、拡張子ごとの数を集計、大文字小文字を区別しない
+// 拡張子は小文字にまとめる
+func (p Path) CountByExtFold() (map[Ext]int, error) {
+	return p.countByExt(Ext.Lower)
+}
+
+// 拡張子ごとの集計処理
+func (p Path) countByExt(key func(Ext) Ext) (map[Ext]int, error) {
+	if !p.IsDir() {
+		return nil, os.ErrNotExist
+	}
+	counts := map[Ext]int{}
+	err := filepath.WalkDir(string(p), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if !d.IsDir() {
+			counts[key(NewPath(path).Ext())]++
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return counts, nil
+}
+
+// ディレクトリ配下のファイル、ディレクトリを再帰的にすべて取得
+// シンボリックリンクのディレクトリは辿らない
+// 読み込めないディレクトリがあっても処理を続け、取得できた Entries とまとめたエラーを返す
+func (p Path) WalkEntries() (Entries, error) {
+	if !p.IsDir() {
+		return Entries{}, fmt.Errorf("%s: %w", p, os.ErrNotExist)
+	}
+	entries := Entries{}
+	var errs []error
+	err := filepath.WalkDir(string(p), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			errs = append(errs, err)
+			return nil
+		}
+		if path != string(p) {
+			entries = append(entries, NewPath(path))
+		}
+		return nil
+	})
+	if err != nil {
+		errs = append(errs, err)
+	}
+	return entries, errors.Join(errs...)
+}
+
+// ディレクトリ配下にファイルがない場合のエラー
+var ErrNoFiles = errors.New("no files")
+
+// ディレクトリ配下で最も更新日時が新しいファイルとその更新日時を取得、ディレクトリは対象外
+// ファイルがない場合は ErrNoFiles を返す
+func (p Path) NewestDescendant() (Path, time.Time, error) {
+	if !p.IsDir() {
+		return "", time.Time{}, fmt.Errorf("%s: %w", p, os.ErrNotExist)
+	}
+	var newest Path
+	var newestTime time.Time
+	err := filepath.WalkDir(string(p), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return nil
+		}
+		fi, err := d.Info()
+		if err != nil {
+			return err
+		}
+		if newest == "" || fi.ModTime().After(newestTime) {
+			newest, newestTime = NewPath(path), fi.ModTime()
+		}
+		return nil
+	})
+	if err != nil {
+		return "", time.Time{}, err
+	}
+	if newest == "" {
+		return "", time.Time{}, ErrNoFiles
+	}
+	return newest, newestTime, nil
+}
+
+// ディレクトリ配下を再帰的に辿り、各要素の root からの相対パスを fn に渡す
+// root 自身は "." として渡す。シンボリックリンクのディレクトリは辿らない
+func (p Path) WalkRel(fn func(rel Path, info fs.DirEntry) error) error {
+	return filepath.WalkDir(string(p), func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := NewPath(path).Rel(p)
+		if err != nil {
+			return err
+		}
+		return fn(rel, d)
+	})
+}
@@ -0,0 +1,112 @@
+package path
+
+import (
+	"errors"
+	"io/fs"
+	"maps"
+	"os"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestListDeep(t *testing.T) {
+	root := buildTestTree(t, map[string]string{
+		"b.txt": "", "a.txt": "", "z/c.txt": "", "y/x/d.txt": "", "y/e.txt": "",
+	})
+	got, err := root.ListDeep()
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := Entries{}
+	for _, name := range []string{"y", "y/x", "y/x/d.txt", "y/e.txt", "z", "z/c.txt", "a.txt", "b.txt"} {
+		want = append(want, Join(root, fromSlash(name)))
+	}
+	if !slices.Equal(got, want) {
+		t.Errorf("ListDeep = %v, want %v", got, want)
+	}
+	if _, err := Join(root, "a.txt").ListDeep(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("ListDeep(file) = %v", err)
+	}
+}
+
+func TestCountByExt(t *testing.T) {
+	root := buildTestTree(t, map[string]string{
+		"a.txt": "", "b.TXT": "", "sub/c.txt": "", "sub/d.go": "", "README": "",
+	})
+	got, err := root.CountByExt()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[Ext]int{".txt": 2, ".TXT": 1, ".go": 1, "": 1}; !maps.Equal(got, want) {
+		t.Errorf("CountByExt = %v, want %v", got, want)
+	}
+	got, err = root.CountByExtFold()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := map[Ext]int{".txt": 3, ".go": 1, "": 1}; !maps.Equal(got, want) {
+		t.Errorf("CountByExtFold = %v, want %v", got, want)
+	}
+}
+
+func TestWalkEntries(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "", "sub/b.txt": ""})
+	if err := os.Symlink(Join(root, "sub").String(), Join(root, "link").String()); err != nil {
+		t.Skip("symlinks not supported:", err)
+	}
+	got, err := root.WalkEntries()
+	if err != nil {
+		t.Fatal(err)
+	}
+	rel := Entries{}
+	for _, p := range got {
+		rel = append(rel, p.TrimPrefix(root))
+	}
+	if want := (Entries{"a.txt", "link", "sub", fromSlash("sub/b.txt")}); !slices.Equal(rel, want) {
+		t.Errorf("WalkEntries = %v, want %v", rel, want)
+	}
+	if _, err := Join(root, "none").WalkEntries(); !errors.Is(err, os.ErrNotExist) {
+		t.Errorf("WalkEntries(missing) = %v", err)
+	}
+}
+
+func TestNewestDescendant(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "", "sub/b.txt": "", "sub/deep/c.txt": ""})
+	base := time.Now().Add(-time.Hour).Truncate(time.Second)
+	for i, name := range []string{"a.txt", "sub/deep/c.txt", "sub/b.txt"} {
+		mt := base.Add(time.Duration(i) * time.Minute)
+		os.Chtimes(Join(root, fromSlash(name)).String(), mt, mt)
+	}
+	// ディレクトリの更新日時は対象外
+	os.Chtimes(Join(root, "sub").String(), time.Now(), time.Now())
+
+	p, mt, err := root.NewestDescendant()
+	if err != nil || p != Join(root, "sub", "b.txt") || !mt.Equal(base.Add(2*time.Minute)) {
+		t.Errorf("NewestDescendant = %s, %v, %v", p, mt, err)
+	}
+
+	empty := Join(root, "empty")
+	empty.CreDir()
+	if _, _, err := empty.NewestDescendant(); !errors.Is(err, ErrNoFiles) {
+		t.Errorf("NewestDescendant(empty) = %v", err)
+	}
+}
+
+func TestWalkRel(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": "", "sub/b.txt": ""})
+	var got Entries
+	err := root.WalkRel(func(rel Path, d fs.DirEntry) error {
+		got = append(got, rel)
+		if rel == "sub" && !d.IsDir() {
+			t.Error("sub is not reported as a directory")
+		}
+		return nil
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := (Entries{".", "a.txt", "sub", fromSlash("sub/b.txt")}); !slices.Equal(got, want) {
+		t.Errorf("WalkRel = %v, want %v", got, want)
+	}
+}
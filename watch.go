@@ -0,0 +1,68 @@
+package path
+
+// ファイルの変更を監視する処理
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"time"
+)
+
+// 変更検出に使うファイルの状態
+type fileState struct {
+	exists  bool
+	modTime time.Time
+	size    int64
+}
+
+// ファイルの状態を取得
+func (p Path) fileState() fileState {
+	fi, err := os.Stat(string(p))
+	if err != nil {
+		return fileState{}
+	}
+	return fileState{exists: true, modTime: fi.ModTime(), size: fi.Size()}
+}
+
+// ファイルの状態が等しいか判定
+func (s fileState) equal(o fileState) bool {
+	return s.exists == o.exists && s.modTime.Equal(o.modTime) && s.size == o.size
+}
+
+// interval ごとにファイルの更新日時とサイズを確認し、変更があればチャネルに通知する
+// 削除や再作成も変更として通知する。ctx がキャンセルされるとチャネルを閉じる
+// 通知を受け取る前に複数回変更された場合、通知は 1 回にまとめられる
+func (p Path) WatchFile(ctx context.Context, interval time.Duration) (<-chan struct{}, error) {
+	if interval <= 0 {
+		return nil, fmt.Errorf("invalid interval: %v", interval)
+	}
+	// ファイルでない場合はエラー
+	if !p.IsFile() {
+		return nil, os.ErrNotExist
+	}
+	ch := make(chan struct{}, 1)
+	prev := p.fileState()
+	go func() {
+		defer close(ch)
+		ticker := time.NewTicker(interval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+			}
+			cur := p.fileState()
+			if cur.equal(prev) {
+				continue
+			}
+			prev = cur
+			select {
+			case ch <- struct{}{}:
+			default:
+			}
+		}
+	}()
+	return ch, nil
+}
@@ -0,0 +1,59 @@
+package path
+
+import (
+	"context"
+	"testing"
+	"time"
+)
+
+func TestWatchFile(t *testing.T) {
+	p := Join(NewPath(t.TempDir()), "a.txt")
+	writeTestFile(t, p, "v1")
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	ch, err := p.WatchFile(ctx, 10*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-ch:
+		t.Fatal("notified without a change")
+	case <-time.After(50 * time.Millisecond):
+	}
+
+	writeTestFile(t, p, "version 2")
+	select {
+	case <-ch:
+	case <-time.After(2 * time.Second):
+		t.Fatal("no notification after a change")
+	}
+
+	cancel()
+	select {
+	case _, ok := <-ch:
+		if ok {
+			// 取りこぼしの通知を読み捨てて、閉じられるのを待つ
+			_, ok = <-ch
+		}
+		if ok {
+			t.Error("channel not closed after cancel")
+		}
+	case <-time.After(2 * time.Second):
+		t.Fatal("channel not closed after cancel")
+	}
+}
+
+func TestWatchFileInvalid(t *testing.T) {
+	root := buildTestTree(t, map[string]string{"a.txt": ""})
+	ctx := context.Background()
+	if _, err := Join(root, "a.txt").WatchFile(ctx, 0); err == nil {
+		t.Error("WatchFile(0) should fail")
+	}
+	if _, err := Join(root, "none").WatchFile(ctx, time.Second); err == nil {
+		t.Error("WatchFile(missing) should fail")
+	}
+	if _, err := root.WatchFile(ctx, time.Second); err == nil {
+		t.Error("WatchFile(dir) should fail")
+	}
+}
@@ -0,0 +1,15 @@
+//go:build !windows && !plan9
+
+package path
+
+// 異なるファイルシステム間の操作の判定 (Windows、Plan 9 以外)
+
+import (
+	"errors"
+	"syscall"
+)
+
+// 異なるファイルシステム間で名前を変更しようとしたエラーか判定
+func isCrossDevice(err error) bool {
+	return errors.Is(err, syscall.EXDEV)
+}
@@ -0,0 +1,11 @@
+//go:build plan9
+
+package path
+
+// 異なるファイルシステム間の操作の判定 (Plan 9)
+
+// 異なるファイルシステム間で名前を変更しようとしたエラーか判定
+// Plan 9 には該当するエラーがないため、常に false を返す
+func isCrossDevice(err error) bool {
+	return false
+}
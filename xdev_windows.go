@@ -0,0 +1,16 @@
+//go:build windows
+
+package path
+
+// 異なるファイルシステム間の操作の判定 (Windows)
+
+import (
+	"errors"
+
+	"golang.org/x/sys/windows"
+)
+
+// 異なるボリューム間で名前を変更しようとしたエラーか判定
+func isCrossDevice(err error) bool {
+	return errors.Is(err, windows.ERROR_NOT_SAME_DEVICE)
+}
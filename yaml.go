@@ -0,0 +1,53 @@
+package path
+
+// YAML ファイルを扱う処理
+
+import (
+	"fmt"
+	"io"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// YAML の解析、変換に失敗した場合のエラー
+// ファイルの読み書きのエラーと区別するために使う
+type YAMLError struct {
+	Path Path
+	Err  error
+}
+
+func (e *YAMLError) Error() string {
+	return fmt.Sprintf("%s: invalid yaml: %v", e.Path, e.Err)
+}
+
+func (e *YAMLError) Unwrap() error {
+	return e.Err
+}
+
+// YAML ファイルを読み込み、T に変換
+// 解析に失敗した場合は *YAMLError を返す
+func ReadYAML[T any](p Path) (T, error) {
+	var v T
+	data, err := os.ReadFile(string(p))
+	if err != nil {
+		return v, fmt.Errorf("%s: %w", p, err)
+	}
+	if err := yaml.Unmarshal(data, &v); err != nil {
+		return v, &YAMLError{Path: p, Err: err}
+	}
+	return v, nil
+}
+
+// v を YAML に変換して原子的に書き込む、親ディレクトリは作成する
+// 変換に失敗した場合は *YAMLError を返す
+func WriteYAML[T any](p Path, v T) error {
+	data, err := yaml.Marshal(v)
+	if err != nil {
+		return &YAMLError{Path: p, Err: err}
+	}
+	return p.writeAtomic(func(w io.Writer) error {
+		_, err := w.Write(data)
+		return err
+	})
+}
@@ -0,0 +1,45 @@
+package path
+
+import (
+	"errors"
+	"os"
+	"testing"
+)
+
+func TestYAML(t *testing.T) {
+	type server struct {
+		Host string `yaml:"host"`
+		Port int    `yaml:"port"`
+	}
+	type config struct {
+		Name    string   `yaml:"name"`
+		Server  server   `yaml:"server"`
+		Plugins []string `yaml:"plugins"`
+	}
+	root := NewPath(t.TempDir())
+	p := Join(root, "conf", "app.yaml")
+	in := config{Name: "app", Server: server{Host: "localhost", Port: 8080}, Plugins: []string{"a", "b"}}
+	if err := WriteYAML(p, in); err != nil {
+		t.Fatal(err)
+	}
+	out, err := ReadYAML[config](p)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if out.Name != in.Name || out.Server != in.Server || len(out.Plugins) != 2 {
+		t.Errorf("ReadYAML = %+v", out)
+	}
+
+	bad := Join(root, "bad.yaml")
+	writeTestFile(t, bad, "name: [unclosed\n")
+	_, err = ReadYAML[config](bad)
+	var yerr *YAMLError
+	if !errors.As(err, &yerr) || yerr.Path != bad {
+		t.Errorf("ReadYAML(invalid) = %v", err)
+	}
+
+	_, err = ReadYAML[config](Join(root, "none.yaml"))
+	if !errors.Is(err, os.ErrNotExist) || errors.As(err, &yerr) {
+		t.Errorf("ReadYAML(missing) = %v", err)
+	}
+}